@@ -0,0 +1,212 @@
+package fastly
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+)
+
+// readCIDRsFile reads a file of CIDRs or IP addresses, one per line. Blank
+// lines and comments starting with # are ignored.
+func readCIDRsFile(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cidrs []string
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			cidrs = append(cidrs, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", path, err)
+	}
+	return cidrs, nil
+}
+
+// ipRange is an inclusive range of IPv4 or IPv6 addresses.
+type ipRange struct {
+	bits       int
+	start, end *big.Int
+}
+
+func parseIPRange(cidr string) (ipRange, error) {
+	if !strings.Contains(cidr, "/") {
+		if strings.Contains(cidr, ":") {
+			cidr += "/128"
+		} else {
+			cidr += "/32"
+		}
+	}
+
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return ipRange{}, fmt.Errorf("invalid IP address or CIDR %q", cidr)
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	ip := ipNet.IP
+	if bits == 32 {
+		ip = ip.To4()
+	}
+
+	start := new(big.Int).SetBytes(ip)
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	end := new(big.Int).Sub(new(big.Int).Add(start, size), big.NewInt(1))
+	return ipRange{bits: bits, start: start, end: end}, nil
+}
+
+// cidrs returns the smallest list of CIDRs covering the range.
+func (r ipRange) cidrs() []string {
+	var cidrs []string
+	one := big.NewInt(1)
+
+	start := new(big.Int).Set(r.start)
+	for start.Cmp(r.end) <= 0 {
+		// The largest block aligned on start, shrunk until it fits the range.
+		hostBits := r.bits
+		if start.Sign() != 0 && int(start.TrailingZeroBits()) < hostBits {
+			hostBits = int(start.TrailingZeroBits())
+		}
+		for {
+			last := new(big.Int).Add(start, new(big.Int).Sub(new(big.Int).Lsh(one, uint(hostBits)), one))
+			if last.Cmp(r.end) <= 0 {
+				break
+			}
+			hostBits--
+		}
+
+		ip := make(net.IP, r.bits/8)
+		start.FillBytes(ip)
+		cidrs = append(cidrs, fmt.Sprintf("%s/%d", ip, r.bits-hostBits))
+
+		start.Add(start, new(big.Int).Lsh(one, uint(hostBits)))
+	}
+
+	return cidrs
+}
+
+// normalizeCIDRs merges the overlapping and adjacent ranges of a list of CIDRs
+// or IP addresses, and returns the smallest list of CIDRs covering them, IPv4
+// first and in address order.
+func normalizeCIDRs(cidrs []string) ([]string, error) {
+	var ranges []ipRange
+	for _, c := range cidrs {
+		r, err := parseIPRange(strings.TrimSpace(c))
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].bits != ranges[j].bits {
+			return ranges[i].bits < ranges[j].bits
+		}
+		return ranges[i].start.Cmp(ranges[j].start) < 0
+	})
+
+	var merged []ipRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && merged[n-1].bits == r.bits {
+			last := &merged[n-1]
+			next := new(big.Int).Add(last.end, big.NewInt(1))
+			if r.start.Cmp(next) <= 0 {
+				if r.end.Cmp(last.end) > 0 {
+					last.end = r.end
+				}
+				continue
+			}
+		}
+		merged = append(merged, ipRange{bits: r.bits, start: r.start, end: r.end})
+	}
+
+	result := []string{}
+	for _, r := range merged {
+		result = append(result, r.cidrs()...)
+	}
+	return result, nil
+}
+
+// aclEntryCIDR returns the CIDR matched by an ACL entry, in the form returned
+// by normalizeCIDRs, and false for the negated entries, which aren't managed
+// through CIDRs.
+func aclEntryCIDR(e *gofastly.ACLEntry) (string, bool) {
+	if e.Negated {
+		return "", false
+	}
+
+	cidr := e.IP
+	if e.Subnet != "" {
+		cidr += "/" + e.Subnet
+	}
+	r, err := parseIPRange(cidr)
+	if err != nil {
+		return "", false
+	}
+	return r.cidrs()[0], true
+}
+
+// diffACLCIDRs returns the operations needed to turn the remote entries of an
+// ACL into the given CIDRs. The remote entries which aren't in cidrs are
+// deleted, or only those of owned when additive. The negated entries, which
+// CIDRs can't express and readACLCIDRs doesn't read, are always left alone.
+func diffACLCIDRs(remote []*gofastly.ACLEntry, cidrs, owned []string, additive bool) []*gofastly.BatchACLEntry {
+	wanted := make(map[string]bool, len(cidrs))
+	for _, c := range cidrs {
+		wanted[c] = true
+	}
+	previous := make(map[string]bool, len(owned))
+	for _, c := range owned {
+		previous[c] = true
+	}
+
+	var ops []*gofastly.BatchACLEntry
+	exists := make(map[string]bool, len(remote))
+	for _, e := range remote {
+		if e.Negated {
+			continue
+		}
+		cidr, ok := aclEntryCIDR(e)
+		if ok && wanted[cidr] && !exists[cidr] {
+			exists[cidr] = true
+			continue
+		}
+		if additive && !(ok && previous[cidr]) {
+			continue
+		}
+		ops = append(ops, &gofastly.BatchACLEntry{
+			Operation: gofastly.DeleteBatchOperation,
+			ID:        gofastly.String(e.ID),
+		})
+	}
+
+	for _, c := range cidrs {
+		if exists[c] {
+			continue
+		}
+		i := strings.LastIndex(c, "/")
+		ops = append(ops, &gofastly.BatchACLEntry{
+			Operation: gofastly.CreateBatchOperation,
+			IP:        gofastly.String(c[:i]),
+			Subnet:    gofastly.String(c[i+1:]),
+			Negated:   gofastly.Bool(false),
+		})
+	}
+
+	return ops
+}
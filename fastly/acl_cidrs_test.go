@@ -0,0 +1,165 @@
+package fastly
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+)
+
+func TestNormalizeCIDRs(t *testing.T) {
+	cases := []struct {
+		name     string
+		cidrs    []string
+		expected []string
+	}{
+		{
+			name:     "empty",
+			cidrs:    nil,
+			expected: []string{},
+		},
+		{
+			name:     "IP addresses",
+			cidrs:    []string{"192.0.2.1", "2001:db8::1"},
+			expected: []string{"192.0.2.1/32", "2001:db8::1/128"},
+		},
+		{
+			name:     "overlapping",
+			cidrs:    []string{"10.0.0.0/8", "10.1.2.0/24", "10.255.255.255"},
+			expected: []string{"10.0.0.0/8"},
+		},
+		{
+			name:     "adjacent",
+			cidrs:    []string{"192.0.2.128/25", "192.0.2.0/25"},
+			expected: []string{"192.0.2.0/24"},
+		},
+		{
+			name:     "adjacent not aligned",
+			cidrs:    []string{"192.0.2.64/26", "192.0.2.128/26"},
+			expected: []string{"192.0.2.64/26", "192.0.2.128/26"},
+		},
+		{
+			name:     "host bits",
+			cidrs:    []string{"192.0.2.77/24"},
+			expected: []string{"192.0.2.0/24"},
+		},
+		{
+			name:     "ordered IPv4 first",
+			cidrs:    []string{"2001:db8::/33", "2001:db8:8000::/33", "203.0.113.0/24", "198.51.100.0/24"},
+			expected: []string{"198.51.100.0/24", "203.0.113.0/24", "2001:db8::/32"},
+		},
+		{
+			name:     "every address",
+			cidrs:    []string{"0.0.0.0/1", "128.0.0.0/1"},
+			expected: []string{"0.0.0.0/0"},
+		},
+	}
+
+	for _, c := range cases {
+		out, err := normalizeCIDRs(c.cidrs)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(out, c.expected) {
+			t.Errorf("%s: Error matching:\nexpected: %#v\n     got: %#v", c.name, c.expected, out)
+		}
+	}
+
+	for _, cidr := range []string{"192.0.2.0/33", "192.0.2", "not an IP"} {
+		if _, err := normalizeCIDRs([]string{cidr}); err == nil {
+			t.Errorf("expected an error normalizing %q", cidr)
+		}
+	}
+}
+
+func TestReadCIDRsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "acl-cidrs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "blocklist.txt")
+	data := "# Blocklist\n192.0.2.0/24\n\n  198.51.100.1  # abusive client\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cidrs, err := readCIDRsFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"192.0.2.0/24", "198.51.100.1"}; !reflect.DeepEqual(cidrs, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, cidrs)
+	}
+}
+
+func TestACLEntryCIDR(t *testing.T) {
+	cases := []struct {
+		entry    *gofastly.ACLEntry
+		expected string
+		ok       bool
+	}{
+		{&gofastly.ACLEntry{IP: "192.0.2.1"}, "192.0.2.1/32", true},
+		{&gofastly.ACLEntry{IP: "192.0.2.1", Subnet: "24"}, "192.0.2.0/24", true},
+		{&gofastly.ACLEntry{IP: "2001:db8::", Subnet: "32"}, "2001:db8::/32", true},
+		{&gofastly.ACLEntry{IP: "192.0.2.1", Negated: true}, "", false},
+	}
+
+	for _, c := range cases {
+		out, ok := aclEntryCIDR(c.entry)
+		if out != c.expected || ok != c.ok {
+			t.Errorf("%#v: expected (%q, %t), got (%q, %t)", c.entry, c.expected, c.ok, out, ok)
+		}
+	}
+}
+
+func TestDiffACLCIDRs(t *testing.T) {
+	remote := []*gofastly.ACLEntry{
+		{ID: "kept", IP: "192.0.2.0", Subnet: "24"},
+		{ID: "previous", IP: "198.51.100.0", Subnet: "24"},
+		{ID: "external", IP: "203.0.113.1"},
+		{ID: "negated", IP: "192.0.2.1", Negated: true},
+	}
+	cidrs := []string{"192.0.2.0/24", "10.0.0.0/8"}
+	owned := []string{"192.0.2.0/24", "198.51.100.0/24"}
+
+	create := &gofastly.BatchACLEntry{
+		Operation: gofastly.CreateBatchOperation,
+		IP:        gofastly.String("10.0.0.0"),
+		Subnet:    gofastly.String("8"),
+		Negated:   gofastly.Bool(false),
+	}
+
+	cases := []struct {
+		additive bool
+		expected []*gofastly.BatchACLEntry
+	}{
+		{
+			additive: false,
+			expected: []*gofastly.BatchACLEntry{
+				{Operation: gofastly.DeleteBatchOperation, ID: gofastly.String("previous")},
+				{Operation: gofastly.DeleteBatchOperation, ID: gofastly.String("external")},
+				create,
+			},
+		},
+		{
+			additive: true,
+			expected: []*gofastly.BatchACLEntry{
+				{Operation: gofastly.DeleteBatchOperation, ID: gofastly.String("previous")},
+				create,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		out := diffACLCIDRs(remote, cidrs, owned, c.additive)
+		if !reflect.DeepEqual(out, c.expected) {
+			t.Errorf("additive %t: Error matching:\nexpected: %#v\n     got: %#v", c.additive, c.expected, out)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const (
+	ActivationCheckDelay      = 5 * time.Second
+	ActivationCheckMinTimeout = 5 * time.Second
+	ActivationRequestTimeout  = 10 * time.Second
+
+	activationStatusHealthy   = "healthy"
+	activationStatusUnhealthy = "unhealthy"
+)
+
+// ActivationHealthCheck requests the health check URL and returns the status
+// code of the response.
+type ActivationHealthCheck func(ctx context.Context, url string) (int, error)
+
+// ActivationChecker polls a health check URL after a service version has been
+// activated until it returns the expected status code.
+type ActivationChecker struct {
+	URL            string
+	ExpectedStatus int
+	Timeout        time.Duration
+	Delay          time.Duration
+	MinTimeout     time.Duration
+	Check          ActivationHealthCheck
+}
+
+func DefaultActivationHealthCheck() ActivationHealthCheck {
+	client := &http.Client{
+		Timeout: ActivationRequestTimeout,
+		// Redirects are not followed so that a redirect can be the expected
+		// response.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return func(ctx context.Context, url string) (int, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+}
+
+func (c *ActivationChecker) waitForHealthy(ctx context.Context) error {
+	// The last result is recorded to explain why the health check failed. It
+	// is guarded as the refresh function runs in its own goroutine.
+	var (
+		last     string
+		lastLock sync.Mutex
+	)
+	setLast := func(result string) string {
+		lastLock.Lock()
+		defer lastLock.Unlock()
+		last = result
+		return result
+	}
+
+	healthStateConf := &resource.StateChangeConf{
+		Pending: []string{
+			activationStatusUnhealthy,
+		},
+		Target: []string{
+			activationStatusHealthy,
+		},
+		Refresh: func() (interface{}, string, error) {
+			status, err := c.Check(ctx, c.URL)
+			if err != nil {
+				// The service may not be reachable until the activation has
+				// propagated, so errors are retried until the timeout.
+				log.Printf("[DEBUG] Health check of (%s) failed: %s", c.URL, err)
+				return setLast(err.Error()), activationStatusUnhealthy, nil
+			}
+			result := setLast(fmt.Sprintf("status %d", status))
+			if status != c.ExpectedStatus {
+				log.Printf("[DEBUG] Health check of (%s) returned %s, expected %d", c.URL, result, c.ExpectedStatus)
+				return result, activationStatusUnhealthy, nil
+			}
+			return result, activationStatusHealthy, nil
+		},
+		Timeout:    c.Timeout,
+		Delay:      c.Delay,
+		MinTimeout: c.MinTimeout,
+	}
+
+	_, err := healthStateConf.WaitForStateContext(ctx)
+	if err != nil {
+		lastLock.Lock()
+		defer lastLock.Unlock()
+		if last != "" {
+			return fmt.Errorf("Error waiting for (%s) to return status %d, last result was %s: %v", c.URL, c.ExpectedStatus, last, err)
+		}
+		return fmt.Errorf("Error waiting for (%s) to return status %d: %v", c.URL, c.ExpectedStatus, err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package fastly
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestActivationChecker_healthy(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first request to simulate the activation propagating.
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	checker := &ActivationChecker{
+		URL:            server.URL,
+		ExpectedStatus: http.StatusNoContent,
+		Timeout:        10 * time.Second,
+		MinTimeout:     10 * time.Millisecond,
+		Check:          DefaultActivationHealthCheck(),
+	}
+
+	if err := checker.waitForHealthy(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestActivationChecker_redirectNotFollowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/elsewhere", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := &ActivationChecker{
+		URL:            server.URL + "/",
+		ExpectedStatus: http.StatusMovedPermanently,
+		Timeout:        10 * time.Second,
+		MinTimeout:     10 * time.Millisecond,
+		Check:          DefaultActivationHealthCheck(),
+	}
+
+	if err := checker.waitForHealthy(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestActivationChecker_timeout(t *testing.T) {
+	cases := map[string]struct {
+		check    ActivationHealthCheck
+		expected string
+	}{
+		"unexpected status": {
+			check: func(context.Context, string) (int, error) {
+				return http.StatusInternalServerError, nil
+			},
+			expected: "last result was status 500",
+		},
+		"request error": {
+			check: func(context.Context, string) (int, error) {
+				return 0, errors.New("connection refused")
+			},
+			expected: "last result was connection refused",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			checker := &ActivationChecker{
+				URL:            "https://www.example.com/health",
+				ExpectedStatus: http.StatusOK,
+				Timeout:        100 * time.Millisecond,
+				MinTimeout:     10 * time.Millisecond,
+				Check:          c.check,
+			}
+
+			err := checker.waitForHealthy(context.Background())
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("expected error to contain %q, got %q", c.expected, err)
+			}
+		})
+	}
+}
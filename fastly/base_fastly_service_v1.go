@@ -3,13 +3,17 @@ package fastly
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 var fastlyNoServiceFoundErr = errors.New("No matching Fastly Service found")
@@ -54,6 +58,16 @@ type DefaultServiceAttributeHandler struct {
 	serviceMetadata ServiceMetadata
 }
 
+// addCustomizeDiff adds a CustomizeDiffFunc to the resource, which allows an
+// attribute handler to validate its attributes against the whole plan.
+func addCustomizeDiff(s *schema.Resource, f schema.CustomizeDiffFunc) {
+	if s.CustomizeDiff == nil {
+		s.CustomizeDiff = f
+		return
+	}
+	s.CustomizeDiff = customdiff.All(s.CustomizeDiff, f)
+}
+
 // GetKey is provided since most attributes will just use their private "key" for interacting with the service.
 func (h *DefaultServiceAttributeHandler) GetKey() string {
 	return h.key
@@ -148,6 +162,10 @@ func resourceService(serviceDef ServiceDefinition) *schema.Resource {
 		UpdateContext: resourceUpdate(serviceDef),
 		DeleteContext: resourceDelete(serviceDef),
 		Importer:      resourceImport(serviceDef),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+		},
 		CustomizeDiff: customdiff.All(
 			customdiff.ComputedIf("cloned_version", func(_ context.Context, d *schema.ResourceDiff, _ interface{}) bool {
 				// If anything other than name, comment and version_comment has changed, the current version will be
@@ -157,6 +175,10 @@ func resourceService(serviceDef ServiceDefinition) *schema.Resource {
 					if changedKey == "name" || changedKey == "comment" || changedKey == "version_comment" {
 						continue
 					}
+					// Neither the activation health check nor the timeouts require a new version.
+					if strings.HasPrefix(changedKey, "activation.") || strings.HasPrefix(changedKey, "timeouts.") {
+						continue
+					}
 					return true
 				}
 				return false
@@ -166,6 +188,7 @@ func resourceService(serviceDef ServiceDefinition) *schema.Resource {
 				// activate flag) then the active_version will be recomputed too.
 				return d.HasChange("cloned_version") && d.Get("activate") == true
 			}),
+			validateShields,
 		),
 
 		Schema: map[string]*schema.Schema{
@@ -216,6 +239,37 @@ func resourceService(serviceDef ServiceDefinition) *schema.Resource {
 				Optional:    true,
 			},
 
+			"activation": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Checks the health of the service after a new version has been activated. If the check does not succeed within the timeout, the previously active version is re-activated",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"health_check_url": {
+							Type:             schema.TypeString,
+							Required:         true,
+							Description:      "The URL to request once a version has been activated. Redirects are not followed",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithHTTPorHTTPS),
+						},
+						"expected_status": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          200,
+							Description:      "The HTTP status code the health check URL is expected to return. Default `200`",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(100, 599)),
+						},
+						"timeout": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          120,
+							Description:      "How long to wait, in seconds, for the health check URL to return the expected status, within the create or update timeout of the resource. Default `120`",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+						},
+					},
+				},
+			},
+
 			"force_destroy": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -231,6 +285,9 @@ func resourceService(serviceDef ServiceDefinition) *schema.Resource {
 		a.Register(s) // Mutates s, adding handler-specific schema items to the list.
 	}
 
+	// References between blocks can only be resolved once every block has been registered.
+	addCustomizeDiff(s, validateReferences(s))
+
 	return s
 }
 
@@ -315,7 +372,8 @@ func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		return diag.FromErr(err)
 	}
 
-	conn := meta.(*FastlyClient).conn
+	client := meta.(*FastlyClient)
+	conn := client.conn
 
 	// Update Name and/or Comment. No new version is required for this.
 	if d.HasChanges("name", "comment") {
@@ -382,10 +440,21 @@ func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, meta int
 			latestVersion = newVersion.Number
 
 			// New versions are not immediately found in the API, or are not
-			// immediately mutable, so we need to sleep a few and let Fastly ready
-			// itself. Typically, 7 seconds is enough.
-			log.Print("[DEBUG] Sleeping 7 seconds to allow Fastly Version to be available")
-			time.Sleep(7 * time.Second)
+			// immediately mutable, so we need to wait for Fastly to ready itself.
+			log.Printf("[DEBUG] Waiting for Fastly Service (%s), Version (%v) to be available", d.Id(), latestVersion)
+			checker := &ServiceVersionChecker{
+				Timeout:    d.Timeout(schema.TimeoutUpdate),
+				Delay:      client.versionCheckDelay,
+				MinTimeout: client.versionCheckInterval,
+				Check:      DefaultServiceVersionChecker(conn),
+			}
+			if err := checker.waitForVersion(ctx, d.Id(), latestVersion); err != nil {
+				// Return early without an error if the Update has been cancelled.
+				if errors.Is(ctx.Err(), context.Canceled) {
+					return nil
+				}
+				return diag.FromErr(err)
+			}
 
 			// Update the cloned version's comment.
 			if d.Get("version_comment").(string) != "" {
@@ -404,22 +473,26 @@ func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		}
 
 		// This delegates the bulk of processing to attribute handlers which manage state
-		// for their own attributes.
+		// for their own attributes. Independent handlers are processed concurrently.
+		var handlers []ServiceAttributeDefinition
 		for _, a := range serviceDef.GetAttributeHandler() {
 			if a.MustProcess(d, initialVersion) {
-				// Check if the Update has been cancelled and return early if so
-				if err := ctx.Err(); err != nil {
-					if errors.Is(err, context.Canceled) {
-						return nil
-					}
-
-					return diag.FromErr(err)
-				}
+				handlers = append(handlers, a)
+			}
+		}
 
-				if err := a.Process(d, latestVersion, conn); err != nil {
-					return diag.FromErr(err)
-				}
+		diags := runServiceHandlers(ctx, client, handlers, func(a ServiceAttributeDefinition, conn *gofastly.Client) error {
+			return a.Process(d, latestVersion, conn)
+		})
+		if diags.HasError() {
+			return diags
+		}
+		// Return early without an error if the Update has been cancelled.
+		if err := ctx.Err(); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
 			}
+			return diag.FromErr(err)
 		}
 
 		// Validate version.
@@ -456,6 +529,19 @@ func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, meta int
 			return diag.Errorf("[ERR] Error activating version (%d): %s", latestVersion, err)
 		}
 
+		// The health check runs after the first activation of a created service
+		// too, within the create or update timeout of the resource.
+		if v, ok := d.GetOk("activation"); ok {
+			timeout := d.Timeout(schema.TimeoutUpdate)
+			if isCreate {
+				timeout = d.Timeout(schema.TimeoutCreate)
+			}
+			previousVersion := d.Get("active_version").(int)
+			if diags := checkActivation(ctx, d, conn, v.([]interface{})[0].(map[string]interface{}), timeout, previousVersion, latestVersion); diags.HasError() {
+				return diags
+			}
+		}
+
 		// Only if the version is valid and activated do we set the active_version.
 		// This prevents us from getting stuck in cloning an invalid version.
 		err = d.Set("active_version", latestVersion)
@@ -467,14 +553,85 @@ func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		log.Print("[INFO] The Terraform definition is explicitly specified to not activate the changes on Fastly")
 		log.Printf("[INFO] Version (%v) has been pushed and validated", latestVersion)
 		log.Printf("[INFO] Visit https://manage.fastly.com/configure/services/%s/versions/%v and activate it manually", d.Id(), latestVersion)
+		log.Print("[INFO] or activate it with a fastly_service_activation resource")
 	}
 
-	return resourceServiceRead(ctx, d, meta, serviceDef, false)
+	return append(resourceServiceRead(ctx, d, meta, serviceDef, false), client.rateLimitWarning()...)
+}
+
+// checkActivation waits for the health check URL of the activation block to
+// return the expected status once latestVersion has been activated, for no
+// longer than the timeout of the block nor the given timeout of the resource.
+// On failure previousVersion is re-activated, unless there is no previous
+// version.
+func checkActivation(ctx context.Context, d *schema.ResourceData, conn *gofastly.Client, activation map[string]interface{}, timeout time.Duration, previousVersion, latestVersion int) diag.Diagnostics {
+	if t := time.Duration(activation["timeout"].(int)) * time.Second; t < timeout {
+		timeout = t
+	}
+
+	checker := &ActivationChecker{
+		URL:            activation["health_check_url"].(string),
+		ExpectedStatus: activation["expected_status"].(int),
+		Timeout:        timeout,
+		Delay:          ActivationCheckDelay,
+		MinTimeout:     ActivationCheckMinTimeout,
+		Check:          DefaultActivationHealthCheck(),
+	}
+
+	log.Printf("[DEBUG] Checking health of Fastly Service (%s), Version (%v)", d.Id(), latestVersion)
+	checkErr := checker.waitForHealthy(ctx)
+	if checkErr == nil {
+		return nil
+	}
+
+	summary := fmt.Sprintf("Health check failed after activating version (%d)", latestVersion)
+	path := cty.Path{cty.GetAttrStep{Name: "activation"}}
+
+	if previousVersion == 0 || previousVersion == latestVersion {
+		return diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       summary,
+				Detail:        fmt.Sprintf("%s. There is no previously active version to re-activate.", checkErr),
+				AttributePath: path,
+			},
+		}
+	}
+
+	log.Printf("[DEBUG] Re-activating Fastly Service (%s), Version (%v)", d.Id(), previousVersion)
+	_, err := conn.ActivateVersion(&gofastly.ActivateVersionInput{
+		ServiceID:      d.Id(),
+		ServiceVersion: previousVersion,
+	})
+	if err != nil {
+		return diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       summary,
+				Detail:        fmt.Sprintf("%s. Re-activating version (%d) also failed: %s", checkErr, previousVersion, err),
+				AttributePath: path,
+			},
+		}
+	}
+
+	if err := d.Set("active_version", previousVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       summary,
+			Detail:        fmt.Sprintf("%s. Version (%d) has been re-activated in place of version (%d).", checkErr, previousVersion, latestVersion),
+			AttributePath: path,
+		},
+	}
 }
 
 // resourceServiceRead provides service resource Read functionality.
 func resourceServiceRead(ctx context.Context, d *schema.ResourceData, meta interface{}, serviceDef ServiceDefinition, isImport bool) diag.Diagnostics {
-	conn := meta.(*FastlyClient).conn
+	client := meta.(*FastlyClient)
+	conn := client.conn
 
 	s, err := conn.GetServiceDetails(&gofastly.GetServiceInput{
 		ID: d.Id(),
@@ -557,20 +714,12 @@ func resourceServiceRead(ctx context.Context, d *schema.ResourceData, meta inter
 	if s.ActiveVersion.Number != 0 {
 
 		// This delegates read to all the attribute handlers which can then manage reading state for
-		// their own attributes.
-		for _, a := range serviceDef.GetAttributeHandler() {
-			// Check if the Read has been cancelled and return early if so
-			if err := ctx.Err(); err != nil {
-				if errors.Is(err, context.Canceled) {
-					return nil
-				}
-
-				return diag.FromErr(err)
-			}
-
-			if err := a.Read(d, s, conn); err != nil {
-				return diag.FromErr(err)
-			}
+		// their own attributes. Independent handlers are read concurrently.
+		diags := runServiceHandlers(ctx, client, serviceDef.GetAttributeHandler(), func(a ServiceAttributeDefinition, conn *gofastly.Client) error {
+			return a.Read(d, s, conn)
+		})
+		if diags.HasError() {
+			return diags
 		}
 	} else if !isImport {
 		log.Printf("[DEBUG] Active Version for Service (%s) is empty, no state to refresh", d.Id())
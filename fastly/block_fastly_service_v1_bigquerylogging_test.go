@@ -319,7 +319,7 @@ func TestResourceFastlyFlattenBigQuery(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenBigQuery(c.remote)
+		out := loggingBigQuery.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
 		}
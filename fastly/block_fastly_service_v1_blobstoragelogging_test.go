@@ -62,7 +62,7 @@ func TestResourceFastlyFlattenBlobStorage(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenBlobStorages(c.remote)
+		out := loggingBlobStorage.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
 		}
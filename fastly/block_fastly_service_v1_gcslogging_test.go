@@ -51,7 +51,7 @@ func TestResourceFastlyFlattenGCS(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenGCS(c.remote)
+		out := loggingGCS.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
 		}
@@ -45,7 +45,7 @@ func TestResourceFastlyFlattenHTTPS(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenHTTPS(c.remote)
+		out := loggingHTTPS.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\n got: %#v", c.local, out)
 		}
@@ -45,7 +45,7 @@ func TestResourceFastlyFlattenLogentries(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenLogentries(c.remote)
+		out := loggingLogentries.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\n got: %#v", c.local, out)
 		}
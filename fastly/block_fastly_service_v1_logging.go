@@ -0,0 +1,381 @@
+package fastly
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// loggingEndpoint is the declarative definition of a logging endpoint block,
+// from which LoggingServiceAttributeHandler builds the schema of the block,
+// the inputs of the API calls and the state. The attributes common to all the
+// endpoints, the name and the VCL only format, format_version, placement and
+// response_condition, are added by the handler so that they behave the same
+// for every endpoint.
+//
+// The attributes are copied to and from the fields of the go-fastly structs
+// with the same name in CamelCase, e.g. tls_ca_cert to TLSCACert.
+type loggingEndpoint struct {
+	// key is the key of the block in the service resources.
+	key string
+	// name is the name of the endpoint in descriptions and log messages.
+	name string
+	// attributes are the attributes of the block specific to the endpoint.
+	attributes map[string]*schema.Schema
+	// fields maps the attributes which are named differently in go-fastly to
+	// the name of their field.
+	fields map[string]string
+	// ints are the attributes saved to state as ints rather than as the
+	// unsigned integers of their go-fastly fields.
+	ints []string
+
+	// format is the default of the format attribute.
+	format string
+	// formatDescription replaces the description of the format attribute.
+	formatDescription string
+	// formatVersion is the default of the format_version attribute. The
+	// attribute is left out of the schema of endpoints without a format
+	// version when it is zero.
+	formatVersion int
+
+	// create, update and delete decode the input into the go-fastly input of
+	// the endpoint and make the API call.
+	create func(conn *gofastly.Client, i *loggingInput) error
+	update func(conn *gofastly.Client, i *loggingInput) error
+	delete func(conn *gofastly.Client, i *loggingInput) error
+	// list returns the endpoints of a service version, as a slice of the
+	// go-fastly structs of the endpoint.
+	list func(conn *gofastly.Client, serviceID string, serviceVersion int) (interface{}, error)
+}
+
+// schema returns the attributes of the block for the type of service.
+func (e *loggingEndpoint) schema(serviceType string) map[string]*schema.Schema {
+	blockAttributes := map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: fmt.Sprintf("The unique name of the %s logging endpoint. It is important to note that changing this attribute will delete and recreate the resource", e.name),
+		},
+	}
+	for k, v := range e.attributes {
+		attr := *v
+		blockAttributes[k] = &attr
+	}
+
+	if serviceType == ServiceTypeVCL {
+		format := &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Apache-style string or VCL variables to use for log formatting.",
+		}
+		if e.format != "" {
+			format.Default = e.format
+			format.Description = fmt.Sprintf("Apache-style string or VCL variables to use for log formatting (default: `%s`).", e.format)
+		}
+		if e.formatDescription != "" {
+			format.Description = e.formatDescription
+		}
+		blockAttributes["format"] = format
+		if e.formatVersion != 0 {
+			blockAttributes["format_version"] = &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          e.formatVersion,
+				Description:      fmt.Sprintf("The version of the custom logging format used for the configured endpoint. Can be either `1` or `2`. (default: `%d`).", e.formatVersion),
+				ValidateDiagFunc: validateLoggingFormatVersion(),
+			}
+		}
+		blockAttributes["response_condition"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The name of an existing condition in the configured endpoint, or leave blank to always execute.",
+		}
+		blockAttributes["placement"] = &schema.Schema{
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "Where in the generated VCL the logging call should be placed. Can be `none` or `waf_debug`.",
+			ValidateDiagFunc: validateLoggingPlacement(),
+		}
+	}
+
+	return blockAttributes
+}
+
+// flatten converts the endpoints returned by list to a list of maps for
+// saving to state. The VCL only attributes are always set.
+func (e *loggingEndpoint) flatten(endpoints interface{}) []map[string]interface{} {
+	attributes := e.schema(ServiceTypeVCL)
+
+	var result []map[string]interface{}
+	list := reflect.ValueOf(endpoints)
+	for i := 0; i < list.Len(); i++ {
+		v := reflect.Indirect(list.Index(i))
+		if !v.IsValid() {
+			continue
+		}
+
+		m := make(map[string]interface{})
+		for attr := range attributes {
+			if f := loggingField(v, attr, e.fields); f.IsValid() {
+				m[attr] = f.Interface()
+			}
+		}
+		for _, attr := range e.ints {
+			if f := loggingField(v, attr, e.fields); f.IsValid() && isIntegerKind(f.Kind()) {
+				m[attr] = int(f.Convert(reflect.TypeOf(int64(0))).Int())
+			}
+		}
+
+		// Prune any empty values that come from the default string value in structs.
+		for k, v := range m {
+			if v == "" {
+				delete(m, k)
+			}
+		}
+
+		result = append(result, m)
+	}
+
+	return result
+}
+
+// loggingInput holds the values of the attributes sent to the API for an
+// endpoint, which decode copies to the go-fastly input of the endpoint.
+type loggingInput struct {
+	serviceID      string
+	serviceVersion int
+	name           string
+	values         map[string]interface{}
+	fields         map[string]string
+}
+
+// decode sets the fields of the go-fastly input i, a pointer to a struct,
+// from the input. Values without a matching field are skipped.
+func (in *loggingInput) decode(i interface{}) {
+	v := reflect.ValueOf(i).Elem()
+	setLoggingField(v.FieldByName("ServiceID"), in.serviceID)
+	setLoggingField(v.FieldByName("ServiceVersion"), in.serviceVersion)
+	setLoggingField(v.FieldByName("Name"), in.name)
+	for attr, value := range in.values {
+		if attr == "name" {
+			continue
+		}
+		setLoggingField(loggingField(v, attr, in.fields), value)
+	}
+}
+
+// loggingField returns the field of the struct v which holds the attribute,
+// or the zero Value if there is none.
+func loggingField(v reflect.Value, attr string, fields map[string]string) reflect.Value {
+	if name, ok := fields[attr]; ok {
+		return v.FieldByName(name)
+	}
+	name := strings.ReplaceAll(attr, "_", "")
+	return v.FieldByNameFunc(func(field string) bool {
+		return strings.EqualFold(field, name)
+	})
+}
+
+// setLoggingField sets the field f to value, converting it to the type of the
+// field. Pointer fields, as found in the update inputs, are set to a pointer
+// to the converted value.
+//
+// NOTE: the integers of the schema are ints while go-fastly mostly uses uints,
+// and booleans are sometimes Compatibools, which is why the values are
+// converted.
+func setLoggingField(f reflect.Value, value interface{}) {
+	if !f.IsValid() || !f.CanSet() || value == nil {
+		return
+	}
+
+	if f.Kind() == reflect.Ptr {
+		p := reflect.New(f.Type().Elem())
+		setLoggingField(p.Elem(), value)
+		f.Set(p)
+		return
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == f.Kind() || isIntegerKind(v.Kind()) && isIntegerKind(f.Kind()) {
+		f.Set(v.Convert(f.Type()))
+	}
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// LoggingServiceAttributeHandler handles the block of a logging endpoint
+// defined by a loggingEndpoint.
+type LoggingServiceAttributeHandler struct {
+	*DefaultServiceAttributeHandler
+	endpoint *loggingEndpoint
+}
+
+// NewServiceLogging returns the handler of the logging endpoint e.
+func NewServiceLogging(sa ServiceMetadata, e *loggingEndpoint) ServiceAttributeDefinition {
+	return &LoggingServiceAttributeHandler{
+		&DefaultServiceAttributeHandler{
+			key:             e.key,
+			serviceMetadata: sa,
+		},
+		e,
+	}
+}
+
+func (h *LoggingServiceAttributeHandler) Process(d *schema.ResourceData, latestVersion int, conn *gofastly.Client) error {
+	serviceID := d.Id()
+	ol, nl := d.GetChange(h.GetKey())
+
+	if ol == nil {
+		ol = new(schema.Set)
+	}
+	if nl == nil {
+		nl = new(schema.Set)
+	}
+
+	oldSet := ol.(*schema.Set)
+	newSet := nl.(*schema.Set)
+
+	setDiff := NewSetDiff(func(resource interface{}) (interface{}, error) {
+		t, ok := resource.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("resource failed to be type asserted: %+v", resource)
+		}
+		return t["name"], nil
+	})
+
+	diffResult, err := setDiff.Diff(oldSet, newSet)
+	if err != nil {
+		return err
+	}
+
+	// DELETE removed resources
+	for _, resource := range diffResult.Deleted {
+		resource := resource.(map[string]interface{})
+		opts := h.buildInput(resource, serviceID, latestVersion, nil)
+
+		log.Printf("[DEBUG] Fastly %s logging endpoint removal opts: %#v", h.endpoint.name, opts)
+
+		if err := h.deleteEndpoint(conn, opts); err != nil {
+			return err
+		}
+	}
+
+	// CREATE new resources
+	for _, resource := range diffResult.Added {
+		resource := resource.(map[string]interface{})
+		opts := h.buildCreate(resource, serviceID, latestVersion)
+
+		log.Printf("[DEBUG] Fastly %s logging addition opts: %#v", h.endpoint.name, opts)
+
+		if err := h.endpoint.create(conn, opts); err != nil {
+			return err
+		}
+	}
+
+	// UPDATE modified resources
+	//
+	// NOTE: although the go-fastly API client enables updating of a resource by
+	// its 'name' attribute, this isn't possible within terraform due to
+	// constraints in the data model/schema of the resources not having a uid.
+	for _, resource := range diffResult.Modified {
+		resource := resource.(map[string]interface{})
+
+		// only attempt to update attributes that have changed
+		modified := setDiff.Filter(resource, oldSet)
+		opts := h.buildInput(resource, serviceID, latestVersion, modified)
+
+		log.Printf("[DEBUG] Update %s Opts: %#v", h.endpoint.name, opts)
+
+		if err := h.endpoint.update(conn, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *LoggingServiceAttributeHandler) Read(d *schema.ResourceData, s *gofastly.ServiceDetail, conn *gofastly.Client) error {
+	log.Printf("[DEBUG] Refreshing %s logging endpoints for (%s)", h.endpoint.name, d.Id())
+	endpoints, err := h.endpoint.list(conn, d.Id(), s.ActiveVersion.Number)
+	if err != nil {
+		return fmt.Errorf("[ERR] Error looking up %s logging endpoints for (%s), version (%v): %s", h.endpoint.name, d.Id(), s.ActiveVersion.Number, err)
+	}
+
+	ell := h.endpoint.flatten(endpoints)
+
+	for _, element := range ell {
+		h.pruneVCLLoggingAttributes(element)
+	}
+
+	if err := d.Set(h.GetKey(), ell); err != nil {
+		log.Printf("[WARN] Error setting %s logging endpoints for (%s): %s", h.endpoint.name, d.Id(), err)
+	}
+
+	return nil
+}
+
+func (h *LoggingServiceAttributeHandler) Register(s *schema.Resource) error {
+	s.Schema[h.GetKey()] = &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: h.endpoint.schema(h.GetServiceMetadata().serviceType),
+		},
+	}
+	return nil
+}
+
+// buildCreate returns the input to create the endpoint, with defaults for the
+// VCL only attributes of Compute services.
+func (h *LoggingServiceAttributeHandler) buildCreate(resource map[string]interface{}, serviceID string, serviceVersion int) *loggingInput {
+	values := make(map[string]interface{}, len(resource))
+	for k, v := range resource {
+		values[k] = v
+	}
+
+	var vla = h.getVCLLoggingAttributes(resource)
+	values["format"] = vla.format
+	values["format_version"] = uintOrDefault(vla.formatVersion)
+	values["placement"] = vla.placement
+	values["response_condition"] = vla.responseCondition
+
+	return h.buildInput(resource, serviceID, serviceVersion, values)
+}
+
+func (h *LoggingServiceAttributeHandler) buildInput(resource map[string]interface{}, serviceID string, serviceVersion int, values map[string]interface{}) *loggingInput {
+	return &loggingInput{
+		serviceID:      serviceID,
+		serviceVersion: serviceVersion,
+		name:           resource["name"].(string),
+		values:         values,
+		fields:         h.endpoint.fields,
+	}
+}
+
+func (h *LoggingServiceAttributeHandler) deleteEndpoint(conn *gofastly.Client, i *loggingInput) error {
+	err := h.endpoint.delete(conn, i)
+
+	errRes, ok := err.(*gofastly.HTTPError)
+	if !ok {
+		return err
+	}
+
+	// 404 response codes don't result in an error propagating because a 404 could
+	// indicate that a resource was deleted elsewhere.
+	if !errRes.IsNotFound() {
+		return err
+	}
+
+	return nil
+}
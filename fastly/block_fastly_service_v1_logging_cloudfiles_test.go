@@ -63,7 +63,7 @@ func TestResourceFastlyFlattenCloudfiles(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenCloudfiles(c.remote)
+		out := loggingCloudfiles.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
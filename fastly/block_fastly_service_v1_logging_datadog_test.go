@@ -39,7 +39,7 @@ func TestResourceFastlyFlattenDatadog(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenDatadog(c.remote)
+		out := loggingDatadog.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
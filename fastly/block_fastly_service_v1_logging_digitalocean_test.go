@@ -63,7 +63,7 @@ func TestResourceFastlyFlattenDigitalOcean(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenDigitalOcean(c.remote)
+		out := loggingDigitalOcean.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
@@ -63,7 +63,7 @@ func TestResourceFastlyFlattenElasticsearch(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenElasticsearch(c.remote)
+		out := loggingElasticsearch.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
@@ -61,7 +61,7 @@ func TestResourceFastlyFlattenFTP(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenFTP(c.remote)
+		out := loggingFTP.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
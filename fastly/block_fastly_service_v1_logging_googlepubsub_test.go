@@ -52,7 +52,7 @@ func TestResourceFastlyFlattenGooglePubSub(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenGooglePubSub(c.remote)
+		out := loggingGooglePubSub.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\n got: %#v", c.local, out)
 		}
@@ -1,238 +1,19 @@
 package fastly
 
 import (
-	"fmt"
-	"log"
-
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-type HerokuServiceAttributeHandler struct {
-	*DefaultServiceAttributeHandler
-}
-
 func NewServiceLoggingHeroku(sa ServiceMetadata) ServiceAttributeDefinition {
-	return &HerokuServiceAttributeHandler{
-		&DefaultServiceAttributeHandler{
-			key:             "logging_heroku",
-			serviceMetadata: sa,
-		},
-	}
-}
-
-func (h *HerokuServiceAttributeHandler) Process(d *schema.ResourceData, latestVersion int, conn *gofastly.Client) error {
-	serviceID := d.Id()
-	ol, nl := d.GetChange(h.GetKey())
-
-	if ol == nil {
-		ol = new(schema.Set)
-	}
-	if nl == nil {
-		nl = new(schema.Set)
-	}
-
-	oldSet := ol.(*schema.Set)
-	newSet := nl.(*schema.Set)
-
-	setDiff := NewSetDiff(func(resource interface{}) (interface{}, error) {
-		t, ok := resource.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("resource failed to be type asserted: %+v", resource)
-		}
-		return t["name"], nil
-	})
-
-	diffResult, err := setDiff.Diff(oldSet, newSet)
-	if err != nil {
-		return err
-	}
-
-	// DELETE removed resources
-	for _, resource := range diffResult.Deleted {
-		resource := resource.(map[string]interface{})
-		opts := h.buildDelete(resource, serviceID, latestVersion)
-
-		log.Printf("[DEBUG] Fastly Heroku logging endpoint removal opts: %#v", opts)
-
-		if err := deleteHeroku(conn, opts); err != nil {
-			return err
-		}
-	}
-
-	// CREATE new resources
-	for _, resource := range diffResult.Added {
-		resource := resource.(map[string]interface{})
-		opts := h.buildCreate(resource, serviceID, latestVersion)
-
-		log.Printf("[DEBUG] Fastly Heroku logging addition opts: %#v", opts)
-
-		if err := createHeroku(conn, opts); err != nil {
-			return err
-		}
-	}
-
-	// UPDATE modified resources
-	//
-	// NOTE: although the go-fastly API client enables updating of a resource by
-	// its 'name' attribute, this isn't possible within terraform due to
-	// constraints in the data model/schema of the resources not having a uid.
-	for _, resource := range diffResult.Modified {
-		resource := resource.(map[string]interface{})
-
-		opts := gofastly.UpdateHerokuInput{
-			ServiceID:      d.Id(),
-			ServiceVersion: latestVersion,
-			Name:           resource["name"].(string),
-		}
-
-		// only attempt to update attributes that have changed
-		modified := setDiff.Filter(resource, oldSet)
-
-		// NOTE: where we transition between interface{} we lose the ability to
-		// infer the underlying type being either a uint vs an int. This
-		// materializes as a panic (yay) and so it's only at runtime we discover
-		// this and so we've updated the below code to convert the type asserted
-		// int into a uint before passing the value to gofastly.Uint().
-		if v, ok := modified["format"]; ok {
-			opts.Format = gofastly.String(v.(string))
-		}
-		if v, ok := modified["format_version"]; ok {
-			opts.FormatVersion = gofastly.Uint(uint(v.(int)))
-		}
-		if v, ok := modified["url"]; ok {
-			opts.URL = gofastly.String(v.(string))
-		}
-		if v, ok := modified["token"]; ok {
-			opts.Token = gofastly.String(v.(string))
-		}
-		if v, ok := modified["response_condition"]; ok {
-			opts.ResponseCondition = gofastly.String(v.(string))
-		}
-		if v, ok := modified["placement"]; ok {
-			opts.Placement = gofastly.String(v.(string))
-		}
-
-		log.Printf("[DEBUG] Update Heroku Opts: %#v", opts)
-		_, err := conn.UpdateHeroku(&opts)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (h *HerokuServiceAttributeHandler) Read(d *schema.ResourceData, s *gofastly.ServiceDetail, conn *gofastly.Client) error {
-	// Refresh Heroku.
-	log.Printf("[DEBUG] Refreshing Heroku logging endpoints for (%s)", d.Id())
-	herokuList, err := conn.ListHerokus(&gofastly.ListHerokusInput{
-		ServiceID:      d.Id(),
-		ServiceVersion: s.ActiveVersion.Number,
-	})
-
-	if err != nil {
-		return fmt.Errorf("[ERR] Error looking up Heroku logging endpoints for (%s), version (%v): %s", d.Id(), s.ActiveVersion.Number, err)
-	}
-
-	ell := flattenHeroku(herokuList)
-
-	for _, element := range ell {
-		element = h.pruneVCLLoggingAttributes(element)
-	}
-
-	if err := d.Set(h.GetKey(), ell); err != nil {
-		log.Printf("[WARN] Error setting Heroku logging endpoints for (%s): %s", d.Id(), err)
-	}
-
-	return nil
+	return NewServiceLogging(sa, loggingHeroku)
 }
 
-func createHeroku(conn *gofastly.Client, i *gofastly.CreateHerokuInput) error {
-	_, err := conn.CreateHeroku(i)
-	return err
-}
-
-func deleteHeroku(conn *gofastly.Client, i *gofastly.DeleteHerokuInput) error {
-	err := conn.DeleteHeroku(i)
-
-	errRes, ok := err.(*gofastly.HTTPError)
-	if !ok {
-		return err
-	}
-
-	// 404 response codes don't result in an error propagating because a 404 could
-	// indicate that a resource was deleted elsewhere.
-	if !errRes.IsNotFound() {
-		return err
-	}
-
-	return nil
-}
-
-func flattenHeroku(herokuList []*gofastly.Heroku) []map[string]interface{} {
-	var res []map[string]interface{}
-	for _, ll := range herokuList {
-		// Convert Heroku logging to a map for saving to state.
-		nll := map[string]interface{}{
-			"name":               ll.Name,
-			"token":              ll.Token,
-			"url":                ll.URL,
-			"format":             ll.Format,
-			"format_version":     ll.FormatVersion,
-			"placement":          ll.Placement,
-			"response_condition": ll.ResponseCondition,
-		}
-
-		// Prune any empty values that come from the default string value in structs.
-		for k, v := range nll {
-			if v == "" {
-				delete(nll, k)
-			}
-		}
-
-		res = append(res, nll)
-	}
-
-	return res
-}
-
-func (h *HerokuServiceAttributeHandler) buildCreate(herokuMap interface{}, serviceID string, serviceVersion int) *gofastly.CreateHerokuInput {
-	df := herokuMap.(map[string]interface{})
-
-	var vla = h.getVCLLoggingAttributes(df)
-	return &gofastly.CreateHerokuInput{
-		ServiceID:         serviceID,
-		ServiceVersion:    serviceVersion,
-		Name:              df["name"].(string),
-		Token:             df["token"].(string),
-		URL:               df["url"].(string),
-		Format:            vla.format,
-		FormatVersion:     uintOrDefault(vla.formatVersion),
-		Placement:         vla.placement,
-		ResponseCondition: vla.responseCondition,
-	}
-}
-
-func (h *HerokuServiceAttributeHandler) buildDelete(herokuMap interface{}, serviceID string, serviceVersion int) *gofastly.DeleteHerokuInput {
-	df := herokuMap.(map[string]interface{})
-
-	return &gofastly.DeleteHerokuInput{
-		ServiceID:      serviceID,
-		ServiceVersion: serviceVersion,
-		Name:           df["name"].(string),
-	}
-}
-
-func (h *HerokuServiceAttributeHandler) Register(s *schema.Resource) error {
-	var blockAttributes = map[string]*schema.Schema{
+var loggingHeroku = &loggingEndpoint{
+	key:  "logging_heroku",
+	name: "Heroku",
+	attributes: map[string]*schema.Schema{
 		// Required fields
-		"name": {
-			Type:        schema.TypeString,
-			Required:    true,
-			Description: "The unique name of the Heroku logging endpoint. It is important to note that changing this attribute will delete and recreate the resource",
-		},
-
 		"token": {
 			Type:        schema.TypeString,
 			Required:    true,
@@ -245,40 +26,30 @@ func (h *HerokuServiceAttributeHandler) Register(s *schema.Resource) error {
 			Required:    true,
 			Description: "The URL to stream logs to",
 		},
-	}
+	},
+	formatVersion: 2,
 
-	if h.GetServiceMetadata().serviceType == ServiceTypeVCL {
-		blockAttributes["format"] = &schema.Schema{
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "Apache-style string or VCL variables to use for log formatting.",
-		}
-		blockAttributes["format_version"] = &schema.Schema{
-			Type:             schema.TypeInt,
-			Optional:         true,
-			Default:          2,
-			Description:      "The version of the custom logging format used for the configured endpoint. Can be either `1` or `2`. (default: `2`).",
-			ValidateDiagFunc: validateLoggingFormatVersion(),
-		}
-		blockAttributes["placement"] = &schema.Schema{
-			Type:             schema.TypeString,
-			Optional:         true,
-			Description:      "Where in the generated VCL the logging call should be placed. Can be `none` or `waf_debug`.",
-			ValidateDiagFunc: validateLoggingPlacement(),
-		}
-		blockAttributes["response_condition"] = &schema.Schema{
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "The name of an existing condition in the configured endpoint, or leave blank to always execute.",
-		}
-	}
-
-	s.Schema[h.GetKey()] = &schema.Schema{
-		Type:     schema.TypeSet,
-		Optional: true,
-		Elem: &schema.Resource{
-			Schema: blockAttributes,
-		},
-	}
-	return nil
+	create: func(conn *gofastly.Client, i *loggingInput) error {
+		var opts gofastly.CreateHerokuInput
+		i.decode(&opts)
+		_, err := conn.CreateHeroku(&opts)
+		return err
+	},
+	update: func(conn *gofastly.Client, i *loggingInput) error {
+		var opts gofastly.UpdateHerokuInput
+		i.decode(&opts)
+		_, err := conn.UpdateHeroku(&opts)
+		return err
+	},
+	delete: func(conn *gofastly.Client, i *loggingInput) error {
+		var opts gofastly.DeleteHerokuInput
+		i.decode(&opts)
+		return conn.DeleteHeroku(&opts)
+	},
+	list: func(conn *gofastly.Client, serviceID string, serviceVersion int) (interface{}, error) {
+		return conn.ListHerokus(&gofastly.ListHerokusInput{
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersion,
+		})
+	},
 }
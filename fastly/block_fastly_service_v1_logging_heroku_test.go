@@ -45,7 +45,7 @@ func TestResourceFastlyFlattenHeroku(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenHeroku(c.remote)
+		out := loggingHeroku.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
@@ -76,7 +76,7 @@ func TestResourceFastlyFlattenHoneycomb(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenHoneycomb(c.remote)
+		out := loggingHoneycomb.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
@@ -69,7 +69,7 @@ func TestResourceFastlyFlattenKafka(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenKafka(c.remote)
+		out := loggingKafka.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
@@ -78,7 +78,7 @@ func TestResourceFastlyFlattenKinesis(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenKinesis(c.remote)
+		out := loggingKinesis.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
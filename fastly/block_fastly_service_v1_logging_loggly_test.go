@@ -37,7 +37,7 @@ func TestResourceFastlyFlattenLoggly(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenLoggly(c.remote)
+		out := loggingLoggly.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
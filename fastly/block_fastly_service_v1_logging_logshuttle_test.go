@@ -45,7 +45,7 @@ func TestResourceFastlyFlattenLogshuttle(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenLogshuttle(c.remote)
+		out := loggingLogshuttle.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
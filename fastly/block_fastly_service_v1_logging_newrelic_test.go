@@ -37,7 +37,7 @@ func TestResourceFastlyFlattenNewRelic(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenNewRelic(c.remote)
+		out := loggingNewRelic.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
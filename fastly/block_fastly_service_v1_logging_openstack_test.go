@@ -62,7 +62,7 @@ func TestResourceFastlyFlattenOpenstack(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenOpenstack(c.remote)
+		out := loggingOpenstack.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
@@ -45,7 +45,7 @@ func TestResourceFastlyFlattenScalyr(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenScalyr(c.remote)
+		out := loggingScalyr.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
@@ -68,7 +68,7 @@ func TestResourceFastlyFlattenSFTP(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenSFTP(c.remote)
+		out := loggingSFTP.flatten(c.remote)
 		if diff := cmp.Diff(out, c.local); diff != "" {
 			t.Fatalf("Error matching: %s", diff)
 		}
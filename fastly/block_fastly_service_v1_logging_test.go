@@ -0,0 +1,123 @@
+package fastly
+
+import (
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoggingEndpointFields(t *testing.T) {
+	cases := []struct {
+		endpoint *loggingEndpoint
+		structs  []interface{}
+	}{
+		{loggingBigQuery, []interface{}{gofastly.BigQuery{}, gofastly.CreateBigQueryInput{}, gofastly.UpdateBigQueryInput{}}},
+		{loggingBlobStorage, []interface{}{gofastly.BlobStorage{}, gofastly.CreateBlobStorageInput{}, gofastly.UpdateBlobStorageInput{}}},
+		{loggingCloudfiles, []interface{}{gofastly.Cloudfiles{}, gofastly.CreateCloudfilesInput{}, gofastly.UpdateCloudfilesInput{}}},
+		{loggingDatadog, []interface{}{gofastly.Datadog{}, gofastly.CreateDatadogInput{}, gofastly.UpdateDatadogInput{}}},
+		{loggingDigitalOcean, []interface{}{gofastly.DigitalOcean{}, gofastly.CreateDigitalOceanInput{}, gofastly.UpdateDigitalOceanInput{}}},
+		{loggingElasticsearch, []interface{}{gofastly.Elasticsearch{}, gofastly.CreateElasticsearchInput{}, gofastly.UpdateElasticsearchInput{}}},
+		{loggingFTP, []interface{}{gofastly.FTP{}, gofastly.CreateFTPInput{}, gofastly.UpdateFTPInput{}}},
+		{loggingGCS, []interface{}{gofastly.GCS{}, gofastly.CreateGCSInput{}, gofastly.UpdateGCSInput{}}},
+		{loggingGooglePubSub, []interface{}{gofastly.Pubsub{}, gofastly.CreatePubsubInput{}, gofastly.UpdatePubsubInput{}}},
+		{loggingHTTPS, []interface{}{gofastly.HTTPS{}, gofastly.CreateHTTPSInput{}, gofastly.UpdateHTTPSInput{}}},
+		{loggingHeroku, []interface{}{gofastly.Heroku{}, gofastly.CreateHerokuInput{}, gofastly.UpdateHerokuInput{}}},
+		{loggingHoneycomb, []interface{}{gofastly.Honeycomb{}, gofastly.CreateHoneycombInput{}, gofastly.UpdateHoneycombInput{}}},
+		{loggingKafka, []interface{}{gofastly.Kafka{}, gofastly.CreateKafkaInput{}, gofastly.UpdateKafkaInput{}}},
+		{loggingKinesis, []interface{}{gofastly.Kinesis{}, gofastly.CreateKinesisInput{}, gofastly.UpdateKinesisInput{}}},
+		{loggingLogentries, []interface{}{gofastly.Logentries{}, gofastly.CreateLogentriesInput{}, gofastly.UpdateLogentriesInput{}}},
+		{loggingLoggly, []interface{}{gofastly.Loggly{}, gofastly.CreateLogglyInput{}, gofastly.UpdateLogglyInput{}}},
+		{loggingLogshuttle, []interface{}{gofastly.Logshuttle{}, gofastly.CreateLogshuttleInput{}, gofastly.UpdateLogshuttleInput{}}},
+		{loggingNewRelic, []interface{}{gofastly.NewRelic{}, gofastly.CreateNewRelicInput{}, gofastly.UpdateNewRelicInput{}}},
+		{loggingOpenstack, []interface{}{gofastly.Openstack{}, gofastly.CreateOpenstackInput{}, gofastly.UpdateOpenstackInput{}}},
+		{loggingPapertrail, []interface{}{gofastly.Papertrail{}, gofastly.CreatePapertrailInput{}, gofastly.UpdatePapertrailInput{}}},
+		{loggingS3, []interface{}{gofastly.S3{}, gofastly.CreateS3Input{}, gofastly.UpdateS3Input{}}},
+		{loggingSFTP, []interface{}{gofastly.SFTP{}, gofastly.CreateSFTPInput{}, gofastly.UpdateSFTPInput{}}},
+		{loggingScalyr, []interface{}{gofastly.Scalyr{}, gofastly.CreateScalyrInput{}, gofastly.UpdateScalyrInput{}}},
+		{loggingSplunk, []interface{}{gofastly.Splunk{}, gofastly.CreateSplunkInput{}, gofastly.UpdateSplunkInput{}}},
+		{loggingSumologic, []interface{}{gofastly.Sumologic{}, gofastly.CreateSumologicInput{}, gofastly.UpdateSumologicInput{}}},
+		{loggingSyslog, []interface{}{gofastly.Syslog{}, gofastly.CreateSyslogInput{}, gofastly.UpdateSyslogInput{}}},
+	}
+
+	for _, c := range cases {
+		attributes := c.endpoint.schema(ServiceTypeVCL)
+
+		// Every renamed field must be the field of an attribute.
+		for attr := range c.endpoint.fields {
+			if _, ok := attributes[attr]; !ok {
+				t.Errorf("%s: the field of %s isn't an attribute", c.endpoint.key, attr)
+			}
+		}
+		for _, attr := range c.endpoint.ints {
+			if _, ok := attributes[attr]; !ok {
+				t.Errorf("%s: the int %s isn't an attribute", c.endpoint.key, attr)
+			}
+		}
+
+		// Every attribute must be read from and sent to the API.
+		for attr := range attributes {
+			for _, s := range c.structs {
+				v := reflect.ValueOf(s)
+				if !loggingField(v, attr, c.endpoint.fields).IsValid() {
+					t.Errorf("%s: no field of %s for the %s attribute", c.endpoint.key, v.Type(), attr)
+				}
+			}
+		}
+	}
+}
+
+func TestLoggingInput_decode(t *testing.T) {
+	in := &loggingInput{
+		serviceID:      "service",
+		serviceVersion: 1,
+		name:           "s3-endpoint",
+		values: map[string]interface{}{
+			"name":           "ignored",
+			"s3_access_key":  "key",
+			"period":         3600,
+			"gzip_level":     9,
+			"redundancy":     "standard",
+			"format_version": uint(2),
+			"unknown":        "ignored",
+		},
+		fields: loggingS3.fields,
+	}
+
+	var create gofastly.CreateS3Input
+	in.decode(&create)
+	if diff := cmp.Diff(create, gofastly.CreateS3Input{
+		ServiceID:      "service",
+		ServiceVersion: 1,
+		Name:           "s3-endpoint",
+		AccessKey:      "key",
+		Period:         3600,
+		GzipLevel:      9,
+		Redundancy:     gofastly.S3RedundancyStandard,
+		FormatVersion:  2,
+	}); diff != "" {
+		t.Errorf("Error matching create input: %s", diff)
+	}
+
+	var update gofastly.UpdateS3Input
+	in.decode(&update)
+	if diff := cmp.Diff(update, gofastly.UpdateS3Input{
+		ServiceID:      "service",
+		ServiceVersion: 1,
+		Name:           "s3-endpoint",
+		AccessKey:      gofastly.String("key"),
+		Period:         gofastly.Uint(3600),
+		GzipLevel:      gofastly.Uint(9),
+		Redundancy:     gofastly.S3RedundancyPtr(gofastly.S3RedundancyStandard),
+		FormatVersion:  gofastly.Uint(2),
+	}); diff != "" {
+		t.Errorf("Error matching update input: %s", diff)
+	}
+
+	var kafka gofastly.UpdateKafkaInput
+	(&loggingInput{values: map[string]interface{}{"use_tls": true}}).decode(&kafka)
+	if kafka.UseTLS == nil || !bool(*kafka.UseTLS) {
+		t.Errorf("Expected use_tls to be set, got %#v", kafka.UseTLS)
+	}
+}
@@ -43,7 +43,7 @@ func TestResourceFastlyFlattenPapertrail(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenPapertrails(c.remote)
+		out := loggingPapertrail.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\n got: %#v", c.local, out)
 		}
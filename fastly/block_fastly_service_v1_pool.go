@@ -0,0 +1,681 @@
+package fastly
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type PoolServiceAttributeHandler struct {
+	*DefaultServiceAttributeHandler
+}
+
+func NewServicePool(sa ServiceMetadata) ServiceAttributeDefinition {
+	return &PoolServiceAttributeHandler{
+		&DefaultServiceAttributeHandler{
+			key:             "pool",
+			serviceMetadata: sa,
+		},
+	}
+}
+
+func (h *PoolServiceAttributeHandler) Process(d *schema.ResourceData, latestVersion int, conn *gofastly.Client) error {
+	op, np := d.GetChange(h.GetKey())
+	if op == nil {
+		op = new(schema.Set)
+	}
+	if np == nil {
+		np = new(schema.Set)
+	}
+
+	oldSet := op.(*schema.Set)
+	newSet := np.(*schema.Set)
+
+	setDiff := NewSetDiff(func(resource interface{}) (interface{}, error) {
+		t, ok := resource.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("resource failed to be type asserted: %+v", resource)
+		}
+		return t["name"], nil
+	})
+
+	diffResult, err := setDiff.Diff(oldSet, newSet)
+	if err != nil {
+		return err
+	}
+
+	// DELETE removed resources
+	//
+	// NOTE: servers belong to a pool and so are removed along with it.
+	for _, resource := range diffResult.Deleted {
+		resource := resource.(map[string]interface{})
+		opts := gofastly.DeletePoolInput{
+			ServiceID:      d.Id(),
+			ServiceVersion: latestVersion,
+			Name:           resource["name"].(string),
+		}
+
+		log.Printf("[DEBUG] Fastly Pool removal opts: %#v", opts)
+		err := conn.DeletePool(&opts)
+		if errRes, ok := err.(*gofastly.HTTPError); ok {
+			if errRes.StatusCode != 404 {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	// CREATE new resources
+	for _, resource := range diffResult.Added {
+		resource := resource.(map[string]interface{})
+		opts := h.buildCreatePoolInput(d.Id(), latestVersion, resource)
+
+		log.Printf("[DEBUG] Create Pool Opts: %#v", opts)
+		pool, err := conn.CreatePool(&opts)
+		if err != nil {
+			return err
+		}
+
+		if v, ok := resource["server"]; ok {
+			for _, server := range v.(*schema.Set).List() {
+				opts := buildCreateServerInput(d.Id(), pool.ID, server.(map[string]interface{}))
+
+				log.Printf("[DEBUG] Create Pool Server Opts: %#v", opts)
+				_, err := conn.CreateServer(&opts)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// UPDATE modified resources
+	//
+	// NOTE: although the go-fastly API client enables updating of a resource by
+	// its 'name' attribute, this isn't possible within terraform due to
+	// constraints in the data model/schema of the resources not having a uid.
+	for _, resource := range diffResult.Modified {
+		resource := resource.(map[string]interface{})
+
+		// only attempt to update attributes that have changed
+		modified := setDiff.Filter(resource, oldSet)
+
+		// The nested server set is always reported as modified by Filter, so
+		// compare it separately and reconcile servers on their own.
+		oldServers := new(schema.Set)
+		for _, o := range oldSet.List() {
+			o := o.(map[string]interface{})
+			if o["name"] == resource["name"] {
+				oldServers = o["server"].(*schema.Set)
+			}
+		}
+		newServers := resource["server"].(*schema.Set)
+		delete(modified, "server")
+
+		var poolID string
+		if len(modified) > 0 {
+			opts := h.buildUpdatePoolInput(d.Id(), latestVersion, resource, modified)
+
+			log.Printf("[DEBUG] Update Pool Opts: %#v", opts)
+			pool, err := conn.UpdatePool(&opts)
+			if err != nil {
+				return err
+			}
+			poolID = pool.ID
+		}
+
+		if !oldServers.Equal(newServers) {
+			if poolID == "" {
+				pool, err := conn.GetPool(&gofastly.GetPoolInput{
+					ServiceID:      d.Id(),
+					ServiceVersion: latestVersion,
+					Name:           resource["name"].(string),
+				})
+				if err != nil {
+					return err
+				}
+				poolID = pool.ID
+			}
+
+			err := processPoolServers(d.Id(), poolID, oldServers, newServers, conn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// processPoolServers reconciles the servers of a single pool. Servers are
+// identified by their address and port as the Fastly API only exposes a
+// generated ID.
+func processPoolServers(serviceID, poolID string, oldSet, newSet *schema.Set, conn *gofastly.Client) error {
+	setDiff := NewSetDiff(func(resource interface{}) (interface{}, error) {
+		t, ok := resource.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("resource failed to be type asserted: %+v", resource)
+		}
+		return poolServerKey(t["address"].(string), uint(t["port"].(int))), nil
+	})
+
+	diffResult, err := setDiff.Diff(oldSet, newSet)
+	if err != nil {
+		return err
+	}
+
+	remoteServers, err := conn.ListServers(&gofastly.ListServersInput{
+		ServiceID: serviceID,
+		PoolID:    poolID,
+	})
+	if err != nil {
+		return err
+	}
+
+	serverIDs := make(map[string]string, len(remoteServers))
+	for _, s := range remoteServers {
+		serverIDs[poolServerKey(s.Address, s.Port)] = s.ID
+	}
+
+	// DELETE removed servers
+	for _, resource := range diffResult.Deleted {
+		resource := resource.(map[string]interface{})
+		id, ok := serverIDs[poolServerKey(resource["address"].(string), uint(resource["port"].(int)))]
+		if !ok {
+			continue
+		}
+
+		opts := gofastly.DeleteServerInput{
+			ServiceID: serviceID,
+			PoolID:    poolID,
+			Server:    id,
+		}
+
+		log.Printf("[DEBUG] Fastly Pool Server removal opts: %#v", opts)
+		err := conn.DeleteServer(&opts)
+		if errRes, ok := err.(*gofastly.HTTPError); ok {
+			if errRes.StatusCode != 404 {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	// CREATE new servers
+	for _, resource := range diffResult.Added {
+		opts := buildCreateServerInput(serviceID, poolID, resource.(map[string]interface{}))
+
+		log.Printf("[DEBUG] Create Pool Server Opts: %#v", opts)
+		_, err := conn.CreateServer(&opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	// UPDATE modified servers
+	for _, resource := range diffResult.Modified {
+		resource := resource.(map[string]interface{})
+		key := poolServerKey(resource["address"].(string), uint(resource["port"].(int)))
+		id, ok := serverIDs[key]
+		if !ok {
+			return fmt.Errorf("[ERR] Unable to find server (%s) in pool (%s)", key, poolID)
+		}
+
+		// only attempt to update attributes that have changed
+		modified := make(map[string]interface{})
+		for _, o := range oldSet.List() {
+			o := o.(map[string]interface{})
+			if o["address"] != resource["address"] || o["port"] != resource["port"] {
+				continue
+			}
+			for k, v := range o {
+				if v != resource[k] {
+					modified[k] = resource[k]
+				}
+			}
+		}
+
+		opts := gofastly.UpdateServerInput{
+			ServiceID: serviceID,
+			PoolID:    poolID,
+			Server:    id,
+		}
+		if v, ok := modified["comment"]; ok {
+			opts.Comment = gofastly.String(v.(string))
+		}
+		if v, ok := modified["weight"]; ok {
+			opts.Weight = gofastly.Uint(uint(v.(int)))
+		}
+		if v, ok := modified["max_conn"]; ok {
+			opts.MaxConn = gofastly.Uint(uint(v.(int)))
+		}
+		if v, ok := modified["disabled"]; ok {
+			opts.Disabled = gofastly.Bool(v.(bool))
+		}
+		if v, ok := modified["override_host"]; ok {
+			opts.OverrideHost = gofastly.String(v.(string))
+		}
+
+		log.Printf("[DEBUG] Update Pool Server Opts: %#v", opts)
+		_, err := conn.UpdateServer(&opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// poolServerKey identifies a server of a pool by its address and port.
+func poolServerKey(address string, port uint) string {
+	return net.JoinHostPort(address, strconv.FormatUint(uint64(port), 10))
+}
+
+func (h *PoolServiceAttributeHandler) buildCreatePoolInput(serviceID string, latestVersion int, resource map[string]interface{}) gofastly.CreatePoolInput {
+	opts := gofastly.CreatePoolInput{
+		ServiceID:        serviceID,
+		ServiceVersion:   latestVersion,
+		Name:             resource["name"].(string),
+		Comment:          resource["comment"].(string),
+		Shield:           resource["shield"].(string),
+		MaxConnDefault:   uint(resource["max_conn_default"].(int)),
+		ConnectTimeout:   uint(resource["connect_timeout"].(int)),
+		FirstByteTimeout: uint(resource["first_byte_timeout"].(int)),
+		Quorum:           uint(resource["quorum"].(int)),
+		UseTLS:           gofastly.Compatibool(resource["use_tls"].(bool)),
+		TLSCACert:        resource["tls_ca_cert"].(string),
+		TLSCiphers:       resource["tls_ciphers"].(string),
+		TLSClientKey:     resource["tls_client_key"].(string),
+		TLSClientCert:    resource["tls_client_cert"].(string),
+		TLSSNIHostname:   resource["tls_sni_hostname"].(string),
+		TLSCheckCert:     gofastly.Compatibool(resource["tls_check_cert"].(bool)),
+		TLSCertHostname:  resource["tls_cert_hostname"].(string),
+		MinTLSVersion:    resource["min_tls_version"].(string),
+		MaxTLSVersion:    resource["max_tls_version"].(string),
+		Healthcheck:      resource["healthcheck"].(string),
+		Type:             gofastly.PoolType(resource["type"].(string)),
+		OverrideHost:     resource["override_host"].(string),
+	}
+
+	if h.GetServiceMetadata().serviceType == ServiceTypeVCL {
+		opts.RequestCondition = resource["request_condition"].(string)
+	}
+	return opts
+}
+
+func (h *PoolServiceAttributeHandler) buildUpdatePoolInput(serviceID string, latestVersion int, resource, modified map[string]interface{}) gofastly.UpdatePoolInput {
+	opts := gofastly.UpdatePoolInput{
+		ServiceID:      serviceID,
+		ServiceVersion: latestVersion,
+		Name:           resource["name"].(string),
+	}
+
+	// NOTE: where we transition between interface{} we lose the ability to
+	// infer the underlying type being either a uint vs an int, so the type
+	// asserted int is converted into a uint before passing it to gofastly.Uint().
+	if v, ok := modified["comment"]; ok {
+		opts.Comment = gofastly.String(v.(string))
+	}
+	if v, ok := modified["shield"]; ok {
+		opts.Shield = gofastly.String(v.(string))
+	}
+	if v, ok := modified["request_condition"]; ok {
+		if h.GetServiceMetadata().serviceType == ServiceTypeVCL {
+			opts.RequestCondition = gofastly.String(v.(string))
+		}
+	}
+	if v, ok := modified["max_conn_default"]; ok {
+		opts.MaxConnDefault = gofastly.Uint(uint(v.(int)))
+	}
+	if v, ok := modified["connect_timeout"]; ok {
+		opts.ConnectTimeout = gofastly.Uint(uint(v.(int)))
+	}
+	if v, ok := modified["first_byte_timeout"]; ok {
+		opts.FirstByteTimeout = gofastly.Uint(uint(v.(int)))
+	}
+	if v, ok := modified["quorum"]; ok {
+		opts.Quorum = gofastly.Uint(uint(v.(int)))
+	}
+	if v, ok := modified["use_tls"]; ok {
+		opts.UseTLS = gofastly.CBool(v.(bool))
+	}
+	if v, ok := modified["tls_ca_cert"]; ok {
+		opts.TLSCACert = gofastly.String(v.(string))
+	}
+	if v, ok := modified["tls_ciphers"]; ok {
+		opts.TLSCiphers = gofastly.String(v.(string))
+	}
+	if v, ok := modified["tls_client_key"]; ok {
+		opts.TLSClientKey = gofastly.String(v.(string))
+	}
+	if v, ok := modified["tls_client_cert"]; ok {
+		opts.TLSClientCert = gofastly.String(v.(string))
+	}
+	if v, ok := modified["tls_sni_hostname"]; ok {
+		opts.TLSSNIHostname = gofastly.String(v.(string))
+	}
+	if v, ok := modified["tls_check_cert"]; ok {
+		opts.TLSCheckCert = gofastly.CBool(v.(bool))
+	}
+	if v, ok := modified["tls_cert_hostname"]; ok {
+		opts.TLSCertHostname = gofastly.String(v.(string))
+	}
+	if v, ok := modified["min_tls_version"]; ok {
+		opts.MinTLSVersion = gofastly.String(v.(string))
+	}
+	if v, ok := modified["max_tls_version"]; ok {
+		opts.MaxTLSVersion = gofastly.String(v.(string))
+	}
+	if v, ok := modified["healthcheck"]; ok {
+		opts.Healthcheck = gofastly.String(v.(string))
+	}
+	if v, ok := modified["type"]; ok {
+		opts.Type = gofastly.PPoolType(gofastly.PoolType(v.(string)))
+	}
+	if v, ok := modified["override_host"]; ok {
+		opts.OverrideHost = gofastly.String(v.(string))
+	}
+
+	return opts
+}
+
+func buildCreateServerInput(serviceID, poolID string, resource map[string]interface{}) gofastly.CreateServerInput {
+	return gofastly.CreateServerInput{
+		ServiceID:    serviceID,
+		PoolID:       poolID,
+		Address:      resource["address"].(string),
+		Comment:      resource["comment"].(string),
+		Weight:       uint(resource["weight"].(int)),
+		MaxConn:      uint(resource["max_conn"].(int)),
+		Port:         uint(resource["port"].(int)),
+		Disabled:     resource["disabled"].(bool),
+		OverrideHost: resource["override_host"].(string),
+	}
+}
+
+func (h *PoolServiceAttributeHandler) Read(d *schema.ResourceData, s *gofastly.ServiceDetail, conn *gofastly.Client) error {
+	log.Printf("[DEBUG] Refreshing Pools for (%s)", d.Id())
+	poolList, err := conn.ListPools(&gofastly.ListPoolsInput{
+		ServiceID:      d.Id(),
+		ServiceVersion: s.ActiveVersion.Number,
+	})
+
+	if err != nil {
+		return fmt.Errorf("[ERR] Error looking up Pools for (%s), version (%v): %s", d.Id(), s.ActiveVersion.Number, err)
+	}
+
+	log.Printf("[DEBUG] Refreshing Pool Servers for (%s)", d.Id())
+	serverList := make(map[string][]*gofastly.Server, len(poolList))
+	for _, pool := range poolList {
+		servers, err := conn.ListServers(&gofastly.ListServersInput{
+			ServiceID: d.Id(),
+			PoolID:    pool.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("[ERR] Error looking up Servers for Pool (%s), service (%s): %s", pool.Name, d.Id(), err)
+		}
+		serverList[pool.ID] = servers
+	}
+
+	pl := flattenPools(poolList, serverList, h.GetServiceMetadata())
+	if err := d.Set(h.GetKey(), pl); err != nil {
+		log.Printf("[WARN] Error setting Pools for (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func (h *PoolServiceAttributeHandler) Register(s *schema.Resource) error {
+	blockAttributes := map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "A unique name to identify this Pool. It is important to note that changing this attribute will delete and recreate the resource",
+		},
+		"comment": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "An optional comment about the Pool",
+		},
+		"connect_timeout": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     1000,
+			Description: "How long to wait for a timeout in milliseconds. Default `1000`",
+		},
+		"first_byte_timeout": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     15000,
+			Description: "How long to wait for the first bytes in milliseconds. Default `15000`",
+		},
+		"healthcheck": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Name of a defined `healthcheck` to assign to this Pool",
+		},
+		"max_conn_default": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     200,
+			Description: "Maximum number of connections for each server in the Pool. Can be overridden by a server's `max_conn`. Default `200`",
+		},
+		"max_tls_version": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Maximum allowed TLS version on TLS connections to the servers of this Pool",
+		},
+		"min_tls_version": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Minimum allowed TLS version on TLS connections to the servers of this Pool",
+		},
+		"override_host": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "The hostname to override the Host header",
+		},
+		"quorum": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          75,
+			Description:      "Percentage of capacity (`0-100`) that needs to be operationally available for the Pool to be considered up. Default `75`",
+			ValidateDiagFunc: validatePoolQuorum(),
+		},
+		"shield": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Selected POP to serve as a \"shield\" for the servers. Valid values for `shield` are included in the [`GET /datacenters`](https://developer.fastly.com/reference/api/utils/datacenter/) API response",
+		},
+		"tls_ca_cert": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "CA certificate attached to origin",
+		},
+		"tls_cert_hostname": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Used for both SNI during the TLS handshake and to validate the cert",
+		},
+		"tls_check_cert": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Be strict about checking TLS certs. Default `true`",
+		},
+		"tls_ciphers": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "List of OpenSSL ciphers (see the [openssl.org manpages](https://www.openssl.org/docs/man1.0.2/man1/ciphers.html) for details)",
+		},
+		"tls_client_cert": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Client certificate attached to origin. Used when connecting to the Pool's servers",
+			Sensitive:   true,
+		},
+		"tls_client_key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Client key attached to origin. Used when connecting to the Pool's servers",
+			Sensitive:   true,
+		},
+		"tls_sni_hostname": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "SNI hostname",
+		},
+		"type": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          string(gofastly.PoolTypeRandom),
+			Description:      "What type of load balance group to use. One of `random`, `hash` or `client`. Default `random`",
+			ValidateDiagFunc: validatePoolType(),
+		},
+		"use_tls": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Whether or not to use TLS to reach the servers. Default `false`",
+		},
+		"server": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "A server within the Pool. Servers are identified by their `address` and `port`. Servers are not versioned, so changes to the servers of an existing Pool apply to the active version straight away, even when `activate` is `false`",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"address": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "A hostname, IPv4, or IPv6 address for the server",
+					},
+					"comment": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "An optional comment about the server",
+					},
+					"disabled": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Allows servers to be enabled and disabled in a Pool. Default `false`",
+					},
+					"max_conn": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Default:     0,
+						Description: "Maximum number of connections. If `0`, the Pool's `max_conn_default` is used. Default `0`",
+					},
+					"override_host": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The hostname to override the Host header. Takes precedence over the Pool's `override_host`",
+					},
+					"port": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Default:     80,
+						Description: "The port number on which the server responds. Default `80`",
+					},
+					"weight": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Default:     100,
+						Description: "Weight (`1-100`) used to load balance this server against others. Default `100`",
+					},
+				},
+			},
+		},
+	}
+
+	if h.GetServiceMetadata().serviceType == ServiceTypeVCL {
+		blockAttributes["request_condition"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Name of a condition, which if met, will select this Pool during a request",
+		}
+	}
+
+	s.Schema[h.GetKey()] = &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: blockAttributes,
+		},
+	}
+
+	return nil
+}
+
+func flattenPools(poolList []*gofastly.Pool, serverList map[string][]*gofastly.Server, sa ServiceMetadata) []map[string]interface{} {
+	pl := make([]map[string]interface{}, 0, len(poolList))
+
+	for _, p := range poolList {
+		pool := map[string]interface{}{
+			"name":               p.Name,
+			"comment":            p.Comment,
+			"connect_timeout":    int(p.ConnectTimeout),
+			"first_byte_timeout": int(p.FirstByteTimeout),
+			"healthcheck":        p.Healthcheck,
+			"max_conn_default":   int(p.MaxConnDefault),
+			"max_tls_version":    p.MaxTLSVersion,
+			"min_tls_version":    p.MinTLSVersion,
+			"override_host":      p.OverrideHost,
+			"quorum":             int(p.Quorum),
+			"shield":             p.Shield,
+			"tls_ca_cert":        p.TLSCACert,
+			"tls_cert_hostname":  p.TLSCertHostname,
+			"tls_check_cert":     p.TLSCheckCert,
+			"tls_ciphers":        p.TLSCiphers,
+			"tls_client_cert":    p.TLSClientCert,
+			"tls_client_key":     p.TLSClientKey,
+			"tls_sni_hostname":   p.TLSSNIHostname,
+			"type":               string(p.Type),
+			"use_tls":            p.UseTLS,
+		}
+
+		if sa.serviceType == ServiceTypeVCL {
+			pool["request_condition"] = p.RequestCondition
+		}
+
+		var servers []interface{}
+		for _, s := range serverList[p.ID] {
+			servers = append(servers, map[string]interface{}{
+				"address":       s.Address,
+				"comment":       s.Comment,
+				"disabled":      s.Disabled,
+				"max_conn":      int(s.MaxConn),
+				"override_host": s.OverrideHost,
+				"port":          int(s.Port),
+				"weight":        int(s.Weight),
+			})
+		}
+		if len(servers) > 0 {
+			pool["server"] = servers
+		}
+
+		pl = append(pl, pool)
+	}
+	return pl
+}
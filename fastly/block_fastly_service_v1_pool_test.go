@@ -0,0 +1,300 @@
+package fastly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceFastlyFlattenPools(t *testing.T) {
+	cases := []struct {
+		remotePools   []*gofastly.Pool
+		remoteServers map[string][]*gofastly.Server
+		local         []map[string]interface{}
+	}{
+		{
+			remotePools: []*gofastly.Pool{
+				{
+					ID:               "pool-id",
+					Name:             "mypool",
+					Comment:          "a pool",
+					Shield:           "lhr-uk",
+					RequestCondition: "is_api",
+					MaxConnDefault:   200,
+					ConnectTimeout:   1000,
+					FirstByteTimeout: 15000,
+					Quorum:           50,
+					UseTLS:           true,
+					TLSCheckCert:     true,
+					TLSCertHostname:  "example.com",
+					Healthcheck:      "myhealthcheck",
+					Type:             gofastly.PoolTypeHash,
+				},
+			},
+			remoteServers: map[string][]*gofastly.Server{
+				"pool-id": {
+					{
+						Address: "10.0.0.1",
+						Weight:  100,
+						Port:    443,
+					},
+				},
+			},
+			local: []map[string]interface{}{
+				{
+					"name":               "mypool",
+					"comment":            "a pool",
+					"connect_timeout":    1000,
+					"first_byte_timeout": 15000,
+					"healthcheck":        "myhealthcheck",
+					"max_conn_default":   200,
+					"max_tls_version":    "",
+					"min_tls_version":    "",
+					"override_host":      "",
+					"quorum":             50,
+					"request_condition":  "is_api",
+					"shield":             "lhr-uk",
+					"tls_ca_cert":        "",
+					"tls_cert_hostname":  "example.com",
+					"tls_check_cert":     true,
+					"tls_ciphers":        "",
+					"tls_client_cert":    "",
+					"tls_client_key":     "",
+					"tls_sni_hostname":   "",
+					"type":               "hash",
+					"use_tls":            true,
+					"server": []interface{}{
+						map[string]interface{}{
+							"address":       "10.0.0.1",
+							"comment":       "",
+							"disabled":      false,
+							"max_conn":      0,
+							"override_host": "",
+							"port":          443,
+							"weight":        100,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		out := flattenPools(c.remotePools, c.remoteServers, ServiceMetadata{ServiceTypeVCL})
+		if !reflect.DeepEqual(out, c.local) {
+			t.Fatalf("Error matching:\nexpected: %#v\n     got: %#v", c.local, out)
+		}
+	}
+}
+
+func TestProcessPoolServers_sameAddressDifferentPort(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/service/service-id/pool/pool-id/servers":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `[
+				{"id": "server-80", "address": "example.com", "port": 80},
+				{"id": "server-8080", "address": "example.com", "port": 8080}
+			]`)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"status": "ok"}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newServer := func(port int) map[string]interface{} {
+		return map[string]interface{}{
+			"address":       "example.com",
+			"port":          port,
+			"comment":       "",
+			"disabled":      false,
+			"max_conn":      0,
+			"override_host": "",
+			"weight":        100,
+		}
+	}
+	hash := func(v interface{}) int {
+		return v.(map[string]interface{})["port"].(int)
+	}
+	oldSet := schema.NewSet(hash, []interface{}{newServer(80), newServer(8080)})
+	newSet := schema.NewSet(hash, []interface{}{newServer(80)})
+
+	if err := processPoolServers("service-id", "pool-id", oldSet, newSet, conn); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/service/service-id/pool/pool-id/server/server-8080"}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, deleted)
+	}
+}
+
+func TestAccFastlyServiceV1_pool_basic(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domainName := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceV1PoolConfig(name, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					testAccCheckFastlyServiceV1PoolServers(&service, "mypool", []string{"developer.fastly.com", "www.fastly.com"}),
+					resource.TestCheckResourceAttr("fastly_service_v1.foo", "pool.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("fastly_service_v1.foo", "pool.*", map[string]string{
+						"name":     "mypool",
+						"type":     "random",
+						"quorum":   "75",
+						"server.#": "2",
+					}),
+				),
+			},
+			{
+				Config: testAccServiceV1PoolConfig_update(name, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					testAccCheckFastlyServiceV1PoolServers(&service, "mypool", []string{"developer.fastly.com", "apps.fastly.com"}),
+					resource.TestCheckResourceAttr("fastly_service_v1.foo", "pool.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("fastly_service_v1.foo", "pool.*", map[string]string{
+						"name":     "mypool",
+						"type":     "hash",
+						"quorum":   "50",
+						"server.#": "2",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("fastly_service_v1.foo", "pool.*.server.*", map[string]string{
+						"address": "developer.fastly.com",
+						"weight":  "50",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckFastlyServiceV1PoolServers(service *gofastly.ServiceDetail, poolName string, addresses []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*FastlyClient).conn
+		pool, err := conn.GetPool(&gofastly.GetPoolInput{
+			ServiceID:      service.ID,
+			ServiceVersion: service.ActiveVersion.Number,
+			Name:           poolName,
+		})
+		if err != nil {
+			return fmt.Errorf("[ERR] Error looking up Pool (%s) for (%s), version (%v): %s", poolName, service.Name, service.ActiveVersion.Number, err)
+		}
+
+		servers, err := conn.ListServers(&gofastly.ListServersInput{
+			ServiceID: service.ID,
+			PoolID:    pool.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("[ERR] Error looking up Servers for Pool (%s): %s", poolName, err)
+		}
+
+		if len(servers) != len(addresses) {
+			return fmt.Errorf("Server count mismatch, expected (%d), got (%d)", len(addresses), len(servers))
+		}
+
+		for _, address := range addresses {
+			var found bool
+			for _, s := range servers {
+				if s.Address == address {
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("Server (%s) not found in Pool (%s)", address, poolName)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccServiceV1PoolConfig(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "developer.fastly.com"
+    name    = "developer"
+  }
+
+  pool {
+    name = "mypool"
+
+    server {
+      address = "developer.fastly.com"
+    }
+
+    server {
+      address = "www.fastly.com"
+    }
+  }
+
+  force_destroy = true
+}`, name, domain)
+}
+
+func testAccServiceV1PoolConfig_update(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "developer.fastly.com"
+    name    = "developer"
+  }
+
+  pool {
+    name   = "mypool"
+    type   = "hash"
+    quorum = 50
+
+    server {
+      address = "developer.fastly.com"
+      weight  = 50
+    }
+
+    server {
+      address = "apps.fastly.com"
+    }
+  }
+
+  force_destroy = true
+}`, name, domain)
+}
@@ -2,200 +2,21 @@ package fastly
 
 import (
 	"fmt"
-	"log"
 
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-type S3LoggingServiceAttributeHandler struct {
-	*DefaultServiceAttributeHandler
-}
-
 func NewServiceS3Logging(sa ServiceMetadata) ServiceAttributeDefinition {
-	return &S3LoggingServiceAttributeHandler{
-		&DefaultServiceAttributeHandler{
-			key:             "s3logging",
-			serviceMetadata: sa,
-		},
-	}
+	return NewServiceLogging(sa, loggingS3)
 }
 
-func (h *S3LoggingServiceAttributeHandler) Process(d *schema.ResourceData, latestVersion int, conn *gofastly.Client) error {
-	serviceID := d.Id()
-
-	os, ns := d.GetChange(h.GetKey())
-	if os == nil {
-		os = new(schema.Set)
-	}
-	if ns == nil {
-		ns = new(schema.Set)
-	}
-
-	oldSet := os.(*schema.Set)
-	newSet := ns.(*schema.Set)
-
-	setDiff := NewSetDiff(func(resource interface{}) (interface{}, error) {
-		t, ok := resource.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("resource failed to be type asserted: %+v", resource)
-		}
-		return t["name"], nil
-	})
-
-	diffResult, err := setDiff.Diff(oldSet, newSet)
-	if err != nil {
-		return err
-	}
-
-	// DELETE removed resources
-	for _, resource := range diffResult.Deleted {
-		opts := h.buildDelete(resource, serviceID, latestVersion)
-		err := deleteS3(conn, opts)
-		if err != nil {
-			return err
-		}
-	}
-
-	// CREATE new resources
-	for _, resource := range diffResult.Added {
-		opts, err := h.buildCreate(resource, d.Id(), latestVersion)
-		if err != nil {
-			return err
-		}
-
-		err = createS3(conn, opts)
-		if err != nil {
-			return err
-		}
-	}
-
-	// UPDATE modified resources
-	//
-	// NOTE: although the go-fastly API client enables updating of a resource by
-	// its 'name' attribute, this isn't possible within terraform due to
-	// constraints in the data model/schema of the resources not having a uid.
-	for _, resource := range diffResult.Modified {
-		resource := resource.(map[string]interface{})
-
-		opts := gofastly.UpdateS3Input{
-			ServiceID:      d.Id(),
-			ServiceVersion: latestVersion,
-			Name:           resource["name"].(string),
-		}
-
-		// only attempt to update attributes that have changed
-		modified := setDiff.Filter(resource, oldSet)
-
-		// NOTE: where we transition between interface{} we lose the ability to
-		// infer the underlying type being either a uint vs an int. This
-		// materializes as a panic (yay) and so it's only at runtime we discover
-		// this and so we've updated the below code to convert the type asserted
-		// int into a uint before passing the value to gofastly.Uint().
-		if v, ok := modified["bucket_name"]; ok {
-			opts.BucketName = gofastly.String(v.(string))
-		}
-		if v, ok := modified["domain"]; ok {
-			opts.Domain = gofastly.String(v.(string))
-		}
-		if v, ok := modified["s3_access_key"]; ok {
-			opts.AccessKey = gofastly.String(v.(string))
-		}
-		if v, ok := modified["s3_secret_key"]; ok {
-			opts.SecretKey = gofastly.String(v.(string))
-		}
-		if v, ok := modified["s3_iam_role"]; ok {
-			opts.IAMRole = gofastly.String(v.(string))
-		}
-		if v, ok := modified["path"]; ok {
-			opts.Path = gofastly.String(v.(string))
-		}
-		if v, ok := modified["period"]; ok {
-			opts.Period = gofastly.Uint(uint(v.(int)))
-		}
-		if v, ok := modified["compression_codec"]; ok {
-			opts.CompressionCodec = gofastly.String(v.(string))
-		}
-		if v, ok := modified["gzip_level"]; ok {
-			opts.GzipLevel = gofastly.Uint(uint(v.(int)))
-		}
-		if v, ok := modified["format"]; ok {
-			opts.Format = gofastly.String(v.(string))
-		}
-		if v, ok := modified["format_version"]; ok {
-			opts.FormatVersion = gofastly.Uint(uint(v.(int)))
-		}
-		if v, ok := modified["response_condition"]; ok {
-			opts.ResponseCondition = gofastly.String(v.(string))
-		}
-		if v, ok := modified["message_type"]; ok {
-			opts.MessageType = gofastly.String(v.(string))
-		}
-		if v, ok := modified["timestamp_format"]; ok {
-			opts.TimestampFormat = gofastly.String(v.(string))
-		}
-		if v, ok := modified["redundancy"]; ok {
-			opts.Redundancy = gofastly.S3RedundancyPtr(gofastly.S3Redundancy(v.(string)))
-		}
-		if v, ok := modified["placement"]; ok {
-			opts.Placement = gofastly.String(v.(string))
-		}
-		if v, ok := modified["public_key"]; ok {
-			opts.PublicKey = gofastly.String(v.(string))
-		}
-		if v, ok := modified["server_side_encryption_kms_key_id"]; ok {
-			opts.ServerSideEncryptionKMSKeyID = gofastly.String(v.(string))
-		}
-		if v, ok := modified["server_side_encryption"]; ok {
-			opts.ServerSideEncryption = gofastly.S3ServerSideEncryptionPtr(gofastly.S3ServerSideEncryption(v.(string)))
-		}
-		if v, ok := modified["acl"]; ok {
-			opts.ACL = gofastly.S3AccessControlListPtr(gofastly.S3AccessControlList(v.(string)))
-		}
-
-		log.Printf("[DEBUG] Update S3 Opts: %#v", opts)
-		_, err := conn.UpdateS3(&opts)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (h *S3LoggingServiceAttributeHandler) Read(d *schema.ResourceData, s *gofastly.ServiceDetail, conn *gofastly.Client) error {
-	// Refresh S3.
-	log.Printf("[DEBUG] Refreshing S3 Logging for (%s)", d.Id())
-	s3List, err := conn.ListS3s(&gofastly.ListS3sInput{
-		ServiceID:      d.Id(),
-		ServiceVersion: s.ActiveVersion.Number,
-	})
-
-	if err != nil {
-		return fmt.Errorf("[ERR] Error looking up S3 Logging for (%s), version (%v): %s", d.Id(), s.ActiveVersion.Number, err)
-	}
-
-	sl := flattenS3s(s3List)
-
-	for _, element := range sl {
-		element = h.pruneVCLLoggingAttributes(element)
-	}
-
-	if err := d.Set(h.GetKey(), sl); err != nil {
-		log.Printf("[WARN] Error setting S3 Logging for (%s): %s", d.Id(), err)
-	}
-	return nil
-}
-
-func (h *S3LoggingServiceAttributeHandler) Register(s *schema.Resource) error {
-	var blockAttributes = map[string]*schema.Schema{
+var loggingS3 = &loggingEndpoint{
+	key:  "s3logging",
+	name: "S3",
+	attributes: map[string]*schema.Schema{
 		// Required fields
-		"name": {
-			Type:        schema.TypeString,
-			Required:    true,
-			Description: "The unique name of the S3 logging endpoint. It is important to note that changing this attribute will delete and recreate the resource",
-		},
 		"bucket_name": {
 			Type:        schema.TypeString,
 			Required:    true,
@@ -321,158 +142,36 @@ func (h *S3LoggingServiceAttributeHandler) Register(s *schema.Resource) error {
 			Description:      `The codec used for compression of your logs. Valid values are zstd, snappy, and gzip. If the specified codec is "gzip", gzip_level will default to 3. To specify a different level, leave compression_codec blank and explicitly set the level using gzip_level. Specifying both compression_codec and gzip_level in the same API request will result in an error.`,
 			ValidateDiagFunc: validateLoggingCompressionCodec(),
 		},
-	}
-
-	if h.GetServiceMetadata().serviceType == ServiceTypeVCL {
-		blockAttributes["format"] = &schema.Schema{
-			Type:        schema.TypeString,
-			Optional:    true,
-			Default:     "%h %l %u %t %r %>s",
-			Description: "Apache-style string or VCL variables to use for log formatting.",
-		}
-		blockAttributes["format_version"] = &schema.Schema{
-			Type:             schema.TypeInt,
-			Optional:         true,
-			Default:          1,
-			Description:      "The version of the custom logging format used for the configured endpoint. Can be either 1 or 2. (Default: 1).",
-			ValidateDiagFunc: validateLoggingFormatVersion(),
-		}
-		blockAttributes["response_condition"] = &schema.Schema{
-			Type:        schema.TypeString,
-			Optional:    true,
-			Default:     "",
-			Description: "Name of blockAttributes condition to apply this logging.",
-		}
-		blockAttributes["placement"] = &schema.Schema{
-			Type:             schema.TypeString,
-			Optional:         true,
-			Description:      "Where in the generated VCL the logging call should be placed.",
-			ValidateDiagFunc: validateLoggingPlacement(),
-		}
-	}
-
-	s.Schema[h.GetKey()] = &schema.Schema{
-		Type:     schema.TypeSet,
-		Optional: true,
-		Elem: &schema.Resource{
-			Schema: blockAttributes,
-		},
-	}
-
-	return nil
-}
-
-func createS3(conn *gofastly.Client, i *gofastly.CreateS3Input) error {
-	_, err := conn.CreateS3(i)
-	return err
-}
-
-func deleteS3(conn *gofastly.Client, i *gofastly.DeleteS3Input) error {
-	log.Printf("[DEBUG] Fastly S3 Logging removal opts: %#v", i)
-
-	err := conn.DeleteS3(i)
-
-	errRes, ok := err.(*gofastly.HTTPError)
-	if !ok {
+	},
+	fields: map[string]string{
+		"s3_access_key": "AccessKey",
+		"s3_secret_key": "SecretKey",
+		"s3_iam_role":   "IAMRole",
+	},
+	format:        "%h %l %u %t %r %>s",
+	formatVersion: 1,
+
+	create: func(conn *gofastly.Client, i *loggingInput) error {
+		var opts gofastly.CreateS3Input
+		i.decode(&opts)
+		_, err := conn.CreateS3(&opts)
 		return err
-	}
-
-	// 404 response codes don't result in an error propagating because a 404 could
-	// indicate that a resource was deleted elsewhere.
-	if !errRes.IsNotFound() {
+	},
+	update: func(conn *gofastly.Client, i *loggingInput) error {
+		var opts gofastly.UpdateS3Input
+		i.decode(&opts)
+		_, err := conn.UpdateS3(&opts)
 		return err
-	}
-
-	return nil
-}
-
-func flattenS3s(s3List []*gofastly.S3) []map[string]interface{} {
-	var sl []map[string]interface{}
-	for _, s := range s3List {
-		// Convert S3s to a map for saving to state.
-		ns := map[string]interface{}{
-			"name":                              s.Name,
-			"bucket_name":                       s.BucketName,
-			"s3_access_key":                     s.AccessKey,
-			"s3_secret_key":                     s.SecretKey,
-			"s3_iam_role":                       s.IAMRole,
-			"path":                              s.Path,
-			"period":                            s.Period,
-			"domain":                            s.Domain,
-			"gzip_level":                        s.GzipLevel,
-			"format":                            s.Format,
-			"format_version":                    s.FormatVersion,
-			"timestamp_format":                  s.TimestampFormat,
-			"redundancy":                        s.Redundancy,
-			"response_condition":                s.ResponseCondition,
-			"message_type":                      s.MessageType,
-			"public_key":                        s.PublicKey,
-			"placement":                         s.Placement,
-			"server_side_encryption":            s.ServerSideEncryption,
-			"server_side_encryption_kms_key_id": s.ServerSideEncryptionKMSKeyID,
-			"compression_codec":                 s.CompressionCodec,
-			"acl":                               s.ACL,
-		}
-
-		// Prune any empty values that come from the default string value in structs.
-		for k, v := range ns {
-			if v == "" {
-				delete(ns, k)
-			}
-		}
-
-		sl = append(sl, ns)
-	}
-
-	return sl
-}
-
-func (h *S3LoggingServiceAttributeHandler) buildCreate(s3Map interface{}, serviceID string, serviceVersion int) (*gofastly.CreateS3Input, error) {
-	df := s3Map.(map[string]interface{})
-
-	var vla = h.getVCLLoggingAttributes(df)
-	opts := gofastly.CreateS3Input{
-		ServiceID:                    serviceID,
-		ServiceVersion:               serviceVersion,
-		Name:                         df["name"].(string),
-		BucketName:                   df["bucket_name"].(string),
-		AccessKey:                    df["s3_access_key"].(string),
-		SecretKey:                    df["s3_secret_key"].(string),
-		IAMRole:                      df["s3_iam_role"].(string),
-		Period:                       uint(df["period"].(int)),
-		GzipLevel:                    uint(df["gzip_level"].(int)),
-		Domain:                       df["domain"].(string),
-		Path:                         df["path"].(string),
-		TimestampFormat:              df["timestamp_format"].(string),
-		MessageType:                  df["message_type"].(string),
-		PublicKey:                    df["public_key"].(string),
-		ServerSideEncryptionKMSKeyID: df["server_side_encryption_kms_key_id"].(string),
-		CompressionCodec:             df["compression_codec"].(string),
-		Format:                       vla.format,
-		FormatVersion:                uintOrDefault(vla.formatVersion),
-		ResponseCondition:            vla.responseCondition,
-		Placement:                    vla.placement,
-		Redundancy:                   gofastly.S3Redundancy(df["redundancy"].(string)),
-		ACL:                          gofastly.S3AccessControlList(df["acl"].(string)),
-	}
-
-	encryption := df["server_side_encryption"].(string)
-	switch encryption {
-	case string(gofastly.S3ServerSideEncryptionAES):
-		opts.ServerSideEncryption = gofastly.S3ServerSideEncryptionAES
-	case string(gofastly.S3ServerSideEncryptionKMS):
-		opts.ServerSideEncryption = gofastly.S3ServerSideEncryptionKMS
-	}
-
-	return &opts, nil
-}
-
-func (h *S3LoggingServiceAttributeHandler) buildDelete(s3Map interface{}, serviceID string, serviceVersion int) *gofastly.DeleteS3Input {
-	df := s3Map.(map[string]interface{})
-
-	return &gofastly.DeleteS3Input{
-		ServiceID:      serviceID,
-		ServiceVersion: serviceVersion,
-		Name:           df["name"].(string),
-	}
+	},
+	delete: func(conn *gofastly.Client, i *loggingInput) error {
+		var opts gofastly.DeleteS3Input
+		i.decode(&opts)
+		return conn.DeleteS3(&opts)
+	},
+	list: func(conn *gofastly.Client, serviceID string, serviceVersion int) (interface{}, error) {
+		return conn.ListS3s(&gofastly.ListS3sInput{
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersion,
+		})
+	},
 }
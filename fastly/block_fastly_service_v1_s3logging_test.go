@@ -125,7 +125,7 @@ func TestResourceFastlyFlattenS3(t *testing.T) {
 
 	for i, c := range cases {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
-			out := flattenS3s(c.remote)
+			out := loggingS3.flatten(c.remote)
 			if diff := cmp.Diff(out, c.local); diff != "" {
 				t.Fatalf("Error matching:%s", diff)
 			}
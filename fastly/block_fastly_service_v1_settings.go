@@ -22,7 +22,9 @@ func (h *SettingsServiceAttributeHandler) Process(d *schema.ResourceData, latest
 		DefaultHost:    gofastly.String(d.Get("default_host").(string)),
 		// default_ttl has the same default value of 3600 that is provided by
 		// the Fastly API, so it's safe to include here
-		DefaultTTL: uint(d.Get("default_ttl").(int)),
+		DefaultTTL:      uint(d.Get("default_ttl").(int)),
+		StaleIfError:    gofastly.Bool(d.Get("stale_if_error").(bool)),
+		StaleIfErrorTTL: gofastly.Uint(uint(d.Get("stale_if_error_ttl").(int))),
 	}
 
 	log.Printf("[DEBUG] Update Settings opts: %#v", opts)
@@ -39,6 +41,8 @@ func (h *SettingsServiceAttributeHandler) Read(d *schema.ResourceData, s *gofast
 	if settings, err := conn.GetSettings(&settingsOpts); err == nil {
 		d.Set("default_host", settings.DefaultHost)
 		d.Set("default_ttl", int(settings.DefaultTTL))
+		d.Set("stale_if_error", settings.StaleIfError)
+		d.Set("stale_if_error_ttl", int(settings.StaleIfErrorTTL))
 	} else {
 		return fmt.Errorf("[ERR] Error looking up Version settings for (%s), version (%v): %s", d.Id(), s.ActiveVersion.Number, err)
 	}
@@ -46,7 +50,7 @@ func (h *SettingsServiceAttributeHandler) Read(d *schema.ResourceData, s *gofast
 }
 
 func (h *SettingsServiceAttributeHandler) HasChange(d *schema.ResourceData) bool {
-	return d.HasChanges("default_ttl", "default_host")
+	return d.HasChanges("default_ttl", "default_host", "stale_if_error", "stale_if_error_ttl")
 }
 
 // If the requested default_ttl is 0, and this is the first
@@ -69,5 +73,17 @@ func (h *SettingsServiceAttributeHandler) Register(s *schema.Resource) error {
 		Optional:    true,
 		Description: "The default hostname",
 	}
+	s.Schema["stale_if_error"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Enables serving a stale object if there is an error. Default `false`",
+	}
+	s.Schema["stale_if_error_ttl"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Optional:    true,
+		Default:     43200,
+		Description: "The default time-to-live (TTL) for serving the stale object for the version. Default `43200`",
+	}
 	return nil
 }
@@ -216,6 +216,10 @@ func (h *SnippetServiceAttributeHandler) Register(s *schema.Resource) error {
 			},
 		},
 	}
+	addCustomizeDiff(s, validateVCLSyntax(h.GetKey(), func(snippet map[string]interface{}) string {
+		snippetType, _ := snippet["type"].(string)
+		return vclSnippetPlacement(snippetType)
+	}))
 	return nil
 }
 
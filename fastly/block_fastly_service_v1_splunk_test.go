@@ -67,7 +67,7 @@ func TestResourceFastlyFlattenSplunk(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenSplunks(c.remote)
+		out := loggingSplunk.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
 		}
@@ -41,7 +41,7 @@ func TestResourceFastlyFlattenSumologic(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenSumologics(c.remote)
+		out := loggingSumologic.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
 		}
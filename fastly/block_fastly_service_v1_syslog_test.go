@@ -64,7 +64,7 @@ func TestResourceFastlyFlattenSyslog(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		out := flattenSyslogs(c.remote)
+		out := loggingSyslog.flatten(c.remote)
 		if !reflect.DeepEqual(out, c.local) {
 			t.Fatalf("Error matching:\nexpected: %#v\n got: %#v", c.local, out)
 		}
@@ -161,6 +161,9 @@ func (h *VCLServiceAttributeHandler) Register(s *schema.Resource) error {
 			},
 		},
 	}
+	addCustomizeDiff(s, validateVCLSyntax(h.GetKey(), func(map[string]interface{}) string {
+		return vclPlacementTopLevel
+	}))
 	return nil
 }
 
@@ -2,6 +2,8 @@ package fastly
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -13,10 +15,57 @@ type Config struct {
 	BaseURL   string
 	UserAgent string
 	NoAuth    bool
+
+	// VersionCheckDelay and VersionCheckInterval control how a cloned service
+	// version is polled until it is available.
+	VersionCheckDelay    time.Duration
+	VersionCheckInterval time.Duration
+
+	// Parallelism is the maximum number of service attribute handlers run
+	// concurrently.
+	Parallelism int
+
+	// MaxRetries, RetryMinWait and RetryMaxWait control how requests which
+	// were rate limited or failed with a transient error are retried.
+	MaxRetries   int
+	RetryMinWait time.Duration
+	RetryMaxWait time.Duration
+
+	// WriteRateLimit and WriteBurst limit the rate of requests modifying
+	// anything through the API. A WriteRateLimit of 0 disables the limit.
+	WriteRateLimit float64
+	WriteBurst     int
+
+	// VCLSyntaxCheck enables checking the syntax of VCL at plan time.
+	VCLSyntaxCheck bool
 }
 
 type FastlyClient struct {
 	conn *gofastly.Client
+
+	// apiKey and baseURL are kept to create further API clients, which do not
+	// share the serialization of requests of conn.
+	apiKey  string
+	baseURL string
+
+	// noAuth is set when the provider is configured without an API key, in
+	// which case only the endpoints not requiring authentication can be used.
+	noAuth bool
+
+	// datacenters caches the list of Fastly datacenters as it is used to
+	// validate the plan of every service resource.
+	datacenters     []gofastly.Datacenter
+	datacentersLock sync.Mutex
+
+	versionCheckDelay    time.Duration
+	versionCheckInterval time.Duration
+
+	parallelism int
+
+	// rateLimit limits the requests modifying anything made by all resources.
+	rateLimit *RateLimitTransport
+
+	vclSyntaxCheck bool
 }
 
 func (c *Config) Client() (*FastlyClient, diag.Diagnostics) {
@@ -33,8 +82,51 @@ func (c *Config) Client() (*FastlyClient, diag.Diagnostics) {
 		return nil, diag.FromErr(err)
 	}
 
-	fastlyClient.HTTPClient.Transport = logging.NewTransport("Fastly", fastlyClient.HTTPClient.Transport)
+	// Retries are rate limited like any other request.
+	client.rateLimit = &RateLimitTransport{
+		Next:  logging.NewTransport("Fastly", fastlyClient.HTTPClient.Transport),
+		Rate:  c.WriteRateLimit,
+		Burst: c.WriteBurst,
+	}
+	fastlyClient.HTTPClient.Transport = &RetryTransport{
+		Next:       client.rateLimit,
+		MaxRetries: c.MaxRetries,
+		MinWait:    c.RetryMinWait,
+		MaxWait:    c.RetryMaxWait,
+	}
 
 	client.conn = fastlyClient
+	client.apiKey = c.ApiKey
+	client.baseURL = c.BaseURL
+	client.noAuth = c.NoAuth && c.ApiKey == ""
+	client.versionCheckDelay = c.VersionCheckDelay
+	client.versionCheckInterval = c.VersionCheckInterval
+	client.parallelism = c.Parallelism
+	client.vclSyntaxCheck = c.VCLSyntaxCheck
 	return &client, nil
 }
+
+// allDatacenters returns the list of Fastly datacenters, which is only fetched
+// from the API once.
+func (c *FastlyClient) allDatacenters() ([]gofastly.Datacenter, error) {
+	c.datacentersLock.Lock()
+	defer c.datacentersLock.Unlock()
+
+	if c.datacenters == nil {
+		datacenters, err := c.conn.AllDatacenters()
+		if err != nil {
+			return nil, err
+		}
+		c.datacenters = datacenters
+	}
+	return c.datacenters, nil
+}
+
+// rateLimitWarning returns a warning once the remaining budget of requests
+// reported by the API runs low.
+func (c *FastlyClient) rateLimitWarning() diag.Diagnostics {
+	if c.rateLimit == nil {
+		return nil
+	}
+	return c.rateLimit.lowBudgetWarning()
+}
@@ -2,6 +2,8 @@ package fastly
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 )
 
@@ -12,6 +14,14 @@ func uintOrDefault(int *uint) uint {
 	return *int
 }
 
+// stripURLScheme strips the scheme from a URL, as the purge and edge check APIs
+// expect only the host and path.
+func stripURLScheme(url string) string {
+	url = strings.TrimPrefix(url, "https://")
+	url = strings.TrimPrefix(url, "http://")
+	return url
+}
+
 // diagToErr takes a diag.Diagnostics and finds the first Error (ignoring Warnings).
 // This is useful for some of the SDK functions which are context aware but still return Go errors, e.g. StateContext
 // and resource.RetryContext.
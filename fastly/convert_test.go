@@ -14,3 +14,18 @@ func TestDefaultUint(t *testing.T) {
 	v := uint(10)
 	assert.Equal(t, v, uintOrDefault(&v))
 }
+
+func TestStripURLScheme(t *testing.T) {
+	for _, testcase := range []struct {
+		input string
+		want  string
+	}{
+		{input: "www.example.com/index.html", want: "www.example.com/index.html"},
+		{input: "http://www.example.com/index.html", want: "www.example.com/index.html"},
+		{input: "https://www.example.com/", want: "www.example.com/"},
+	} {
+		if got := stripURLScheme(testcase.input); got != testcase.want {
+			t.Errorf("stripURLScheme(%q) = %q, want %q", testcase.input, got, testcase.want)
+		}
+	}
+}
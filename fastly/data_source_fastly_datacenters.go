@@ -0,0 +1,105 @@
+package fastly
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/fastly/terraform-provider-fastly/fastly/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceFastlyDatacenters() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyDatacentersRead,
+
+		Schema: map[string]*schema.Schema{
+			"pops": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The list of datacenters (POPs) of the Fastly network, ordered by code.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"code": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The code of the POP (e.g. `LCY`).",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the POP.",
+						},
+						"group": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The region the POP belongs to (e.g. `Europe`).",
+						},
+						"shield": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The value to use in the `shield` attribute of a backend or director to use the POP as a shield. Empty when the POP cannot be used as a shield.",
+						},
+						"latitude": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "The latitude of the POP.",
+						},
+						"longitude": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "The longitude of the POP.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFastlyDatacentersRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	log.Printf("[DEBUG] Reading datacenters")
+
+	datacenters, err := conn.AllDatacenters()
+	if err != nil {
+		return diag.Errorf("Error listing datacenters: %s", err)
+	}
+
+	pops := flattenDatacenters(datacenters)
+
+	var codes []string
+	for _, p := range pops {
+		codes = append(codes, p["code"].(string))
+	}
+	d.SetId(hashcode.Strings(codes))
+
+	if err := d.Set("pops", pops); err != nil {
+		return diag.Errorf("Error setting pops: %s", err)
+	}
+
+	return nil
+}
+
+func flattenDatacenters(datacenters []gofastly.Datacenter) []map[string]interface{} {
+	var pops []map[string]interface{}
+	for _, dc := range datacenters {
+		pops = append(pops, map[string]interface{}{
+			"code":      dc.Code,
+			"name":      dc.Name,
+			"group":     dc.Group,
+			"shield":    dc.Shield,
+			"latitude":  dc.Coordinates.Latitude,
+			"longitude": dc.Coordinates.Longtitude,
+		})
+	}
+
+	sort.SliceStable(pops, func(i, j int) bool {
+		return pops[i]["code"].(string) < pops[j]["code"].(string)
+	})
+
+	return pops
+}
@@ -0,0 +1,89 @@
+package fastly
+
+import (
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFlattenDatacenters(t *testing.T) {
+	cases := []struct {
+		remote []gofastly.Datacenter
+		local  []map[string]interface{}
+	}{
+		{
+			remote: []gofastly.Datacenter{
+				{
+					Code:   "SJC",
+					Name:   "San Jose",
+					Group:  "North America",
+					Shield: "sjc-ca-us",
+					Coordinates: gofastly.Coordinates{
+						Latitude:   37.3382,
+						Longtitude: -121.8863,
+					},
+				},
+				{
+					Code:   "LCY",
+					Name:   "London City",
+					Group:  "Europe",
+					Shield: "london_city-uk",
+					Coordinates: gofastly.Coordinates{
+						Latitude:   51.5072,
+						Longtitude: -0.1275,
+					},
+				},
+			},
+			local: []map[string]interface{}{
+				{
+					"code":      "LCY",
+					"name":      "London City",
+					"group":     "Europe",
+					"shield":    "london_city-uk",
+					"latitude":  51.5072,
+					"longitude": -0.1275,
+				},
+				{
+					"code":      "SJC",
+					"name":      "San Jose",
+					"group":     "North America",
+					"shield":    "sjc-ca-us",
+					"latitude":  37.3382,
+					"longitude": -121.8863,
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		out := flattenDatacenters(c.remote)
+		if !reflect.DeepEqual(out, c.local) {
+			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
+		}
+	}
+}
+
+func TestAccFastlyDataSourceDatacenters_basic(t *testing.T) {
+	resourceName := "data.fastly_datacenters.some"
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFastlyDataSourceDatacentersConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "pops.#"),
+					resource.TestCheckResourceAttrSet(resourceName, "pops.0.code"),
+					resource.TestCheckResourceAttrSet(resourceName, "pops.0.name"),
+					resource.TestCheckResourceAttrSet(resourceName, "pops.0.group"),
+				),
+			},
+		},
+	})
+}
+
+const testAccFastlyDataSourceDatacentersConfig = `
+data "fastly_datacenters" "some" {}
+`
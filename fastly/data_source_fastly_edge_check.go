@@ -0,0 +1,207 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/fastly/terraform-provider-fastly/fastly/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceFastlyEdgeCheck() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyEdgeCheckRead,
+
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "The full URL to check on the edge (e.g. `www.example.com/index.html`). A leading `http://` or `https://` scheme is ignored.",
+			},
+			"consistent": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the content returned by every server has the same hash and response status.",
+			},
+			"hashes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The lexically ordered list of distinct content hashes returned by the servers.",
+			},
+			"results": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The result of the check for each server, ordered by server name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"server": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the cache server that was checked, including the POP code (e.g. `cache-lcy19230-LCY`).",
+						},
+						"pop": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The code of the POP the server belongs to (e.g. `LCY`).",
+						},
+						"hash": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The hash of the content returned by the server.",
+						},
+						"response_time": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "The time in seconds it took the server to respond.",
+						},
+						"request_url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The URL that was requested from the server.",
+						},
+						"request_method": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The HTTP method used for the request.",
+						},
+						"request_headers": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The headers sent with the request. Multiple values of the same header are joined with `, `.",
+						},
+						"status": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The HTTP status code of the response.",
+						},
+						"response_headers": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The headers of the response. Multiple values of the same header are joined with `, `.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFastlyEdgeCheckRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	url := stripURLScheme(d.Get("url").(string))
+
+	log.Printf("[DEBUG] Running edge check for URL (%s)", url)
+
+	checks, err := conn.EdgeCheck(&gofastly.EdgeCheckInput{
+		URL: url,
+	})
+	if err != nil {
+		return diag.Errorf("Error running edge check for URL (%s): %s", url, err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", hashcode.String(url)))
+
+	if err := d.Set("consistent", edgeChecksConsistent(checks)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("hashes", edgeCheckHashes(checks)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("results", flattenEdgeChecks(checks)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenEdgeChecks(checks []*gofastly.EdgeCheck) []map[string]interface{} {
+	var results []map[string]interface{}
+	for _, c := range checks {
+		result := map[string]interface{}{
+			"server":        c.Server,
+			"pop":           edgeCheckPOP(c.Server),
+			"hash":          c.Hash,
+			"response_time": c.ResponseTime,
+		}
+		if c.Request != nil {
+			result["request_url"] = c.Request.URL
+			result["request_method"] = c.Request.Method
+			result["request_headers"] = flattenEdgeCheckHeaders(c.Request.Headers)
+		}
+		if c.Response != nil {
+			result["status"] = int(c.Response.Status)
+			result["response_headers"] = flattenEdgeCheckHeaders(c.Response.Headers)
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i]["server"].(string) < results[j]["server"].(string)
+	})
+
+	return results
+}
+
+// edgeCheckPOP returns the POP code at the end of the name of a cache server,
+// e.g. `LCY` for `cache-lcy19230-LCY`.
+func edgeCheckPOP(server string) string {
+	return server[strings.LastIndex(server, "-")+1:]
+}
+
+// flattenEdgeCheckHeaders converts HTTP headers to a map of strings, joining
+// multiple values of the same header as they would be folded in a single line.
+func flattenEdgeCheckHeaders(h *http.Header) map[string]interface{} {
+	headers := make(map[string]interface{})
+	if h == nil {
+		return headers
+	}
+	for k, v := range *h {
+		headers[k] = strings.Join(v, ", ")
+	}
+	return headers
+}
+
+// edgeCheckHashes returns the distinct content hashes of the edge checks in
+// lexical order.
+func edgeCheckHashes(checks []*gofastly.EdgeCheck) []string {
+	seen := make(map[string]bool)
+	var hashes []string
+	for _, c := range checks {
+		if !seen[c.Hash] {
+			seen[c.Hash] = true
+			hashes = append(hashes, c.Hash)
+		}
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+// edgeChecksConsistent reports whether every server returned the same content
+// hash and response status.
+func edgeChecksConsistent(checks []*gofastly.EdgeCheck) bool {
+	for i := 1; i < len(checks); i++ {
+		if checks[i].Hash != checks[0].Hash || edgeCheckStatus(checks[i]) != edgeCheckStatus(checks[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+func edgeCheckStatus(c *gofastly.EdgeCheck) uint {
+	if c.Response == nil {
+		return 0
+	}
+	return c.Response.Status
+}
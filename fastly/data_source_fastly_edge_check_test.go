@@ -0,0 +1,171 @@
+package fastly
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFlattenEdgeChecks(t *testing.T) {
+	cases := []struct {
+		remote []*gofastly.EdgeCheck
+		local  []map[string]interface{}
+	}{
+		{
+			remote: []*gofastly.EdgeCheck{
+				{
+					Hash:         "abc",
+					Server:       "cache-sjc3128-SJC",
+					ResponseTime: 0.25,
+					Request: &gofastly.EdgeCheckRequest{
+						URL:    "http://www.example.com/",
+						Method: "GET",
+						Headers: &http.Header{
+							"Host": []string{"www.example.com"},
+						},
+					},
+					Response: &gofastly.EdgeCheckResponse{
+						Status: 200,
+						Headers: &http.Header{
+							"Vary": []string{"Accept-Encoding", "Cookie"},
+						},
+					},
+				},
+				{
+					Hash:   "abc",
+					Server: "cache-lcy19230-LCY",
+				},
+			},
+			local: []map[string]interface{}{
+				{
+					"server":        "cache-lcy19230-LCY",
+					"pop":           "LCY",
+					"hash":          "abc",
+					"response_time": 0.0,
+				},
+				{
+					"server":           "cache-sjc3128-SJC",
+					"pop":              "SJC",
+					"hash":             "abc",
+					"response_time":    0.25,
+					"request_url":      "http://www.example.com/",
+					"request_method":   "GET",
+					"request_headers":  map[string]interface{}{"Host": "www.example.com"},
+					"status":           200,
+					"response_headers": map[string]interface{}{"Vary": "Accept-Encoding, Cookie"},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		out := flattenEdgeChecks(c.remote)
+		if !reflect.DeepEqual(out, c.local) {
+			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
+		}
+	}
+}
+
+func TestEdgeChecksConsistent(t *testing.T) {
+	ok := &gofastly.EdgeCheckResponse{Status: 200}
+	notFound := &gofastly.EdgeCheckResponse{Status: 404}
+
+	cases := map[string]struct {
+		checks     []*gofastly.EdgeCheck
+		consistent bool
+		hashes     []string
+	}{
+		"no checks": {
+			checks:     nil,
+			consistent: true,
+			hashes:     nil,
+		},
+		"same hash and status": {
+			checks: []*gofastly.EdgeCheck{
+				{Hash: "abc", Response: ok},
+				{Hash: "abc", Response: ok},
+			},
+			consistent: true,
+			hashes:     []string{"abc"},
+		},
+		"different hash": {
+			checks: []*gofastly.EdgeCheck{
+				{Hash: "def", Response: ok},
+				{Hash: "abc", Response: ok},
+				{Hash: "def", Response: ok},
+			},
+			consistent: false,
+			hashes:     []string{"abc", "def"},
+		},
+		"different status": {
+			checks: []*gofastly.EdgeCheck{
+				{Hash: "abc", Response: ok},
+				{Hash: "abc", Response: notFound},
+			},
+			consistent: false,
+			hashes:     []string{"abc"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if consistent := edgeChecksConsistent(c.checks); consistent != c.consistent {
+				t.Errorf("expected consistent to be %t, got %t", c.consistent, consistent)
+			}
+			if hashes := edgeCheckHashes(c.checks); !reflect.DeepEqual(hashes, c.hashes) {
+				t.Errorf("expected hashes %#v, got %#v", c.hashes, hashes)
+			}
+		})
+	}
+}
+
+func TestAccFastlyDataSourceEdgeCheck_basic(t *testing.T) {
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domainName := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+	dataSourceName := "data.fastly_edge_check.index"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFastlyDataSourceEdgeCheckConfig(name, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "consistent"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "results.0.server"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "results.0.hash"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "results.0.status"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFastlyDataSourceEdgeCheckConfig(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  force_destroy = true
+}
+
+data "fastly_edge_check" "index" {
+  url = "http://${tolist(fastly_service_v1.foo.domain)[0].name}/"
+}`, name, domain)
+}
@@ -0,0 +1,299 @@
+package fastly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/fastly/terraform-provider-fastly/fastly/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	// DefaultEventsPageSize is the number of events requested per page.
+	DefaultEventsPageSize = 100
+	// DefaultMaxEvents is the number of events after which no more pages are
+	// requested.
+	DefaultMaxEvents = 1000
+)
+
+func dataSourceFastlyEvents() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyEventsRead,
+
+		Schema: map[string]*schema.Schema{
+			"service_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Limit the events to those of a service.",
+			},
+			"event_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Limit the events to a type of event, e.g. `version.activate`.",
+			},
+			"user_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Limit the events to those caused by a user.",
+			},
+			"customer_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Limit the events to those of a customer account.",
+			},
+			"page_size": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          DefaultEventsPageSize,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      fmt.Sprintf("The number of events requested per page. Pages are requested until `max_events` events are read. Default `%d`.", DefaultEventsPageSize),
+			},
+			"max_events": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          DefaultMaxEvents,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      fmt.Sprintf("The maximum number of the most recent events to read. Default `%d`.", DefaultMaxEvents),
+			},
+			"events": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching events, most recent first.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the event.",
+						},
+						"event_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the event, e.g. `version.activate`.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The description of the event.",
+						},
+						"service_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the service the event relates to.",
+						},
+						"customer_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the customer account the event relates to.",
+						},
+						"user_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the user who caused the event.",
+						},
+						"ip": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The IP address the event was caused from.",
+						},
+						"admin": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the event was caused by a Fastly administrator.",
+						},
+						"metadata": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The details of the event, such as the activated `version`. Values which aren't strings are JSON encoded.",
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Timestamp (GMT) when the event happened.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFastlyEventsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	log.Printf("[DEBUG] Listing events")
+
+	events, err := listEvents(conn, &gofastly.GetAPIEventsFilterInput{
+		ServiceID:  d.Get("service_id").(string),
+		EventType:  d.Get("event_type").(string),
+		UserID:     d.Get("user_id").(string),
+		CustomerID: d.Get("customer_id").(string),
+		MaxResults: d.Get("page_size").(int),
+	}, d.Get("max_events").(int))
+	if err != nil {
+		return diag.Errorf("Error listing events: %s", err)
+	}
+
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+
+	d.SetId(hashcode.Strings(ids))
+	if err := d.Set("events", flattenEvents(events)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// listEvents fetches the pages of the events matching the filter, most recent
+// first, until max events are read, and returns at most max events.
+func listEvents(conn *gofastly.Client, i *gofastly.GetAPIEventsFilterInput, max int) ([]*gofastly.Event, error) {
+	var events []*gofastly.Event
+	for page := 1; ; page++ {
+		i.PageNumber = page
+		resp, err := getEventsPage(conn, i)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, resp.Events...)
+
+		if len(events) >= max {
+			events = events[:max]
+			break
+		}
+		if resp.Links.Next == "" || len(resp.Events) == 0 {
+			break
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].CreatedAt == nil || events[j].CreatedAt == nil {
+			return events[j].CreatedAt == nil && events[i].CreatedAt != nil
+		}
+		return events[i].CreatedAt.After(*events[j].CreatedAt)
+	})
+
+	return events, nil
+}
+
+// getEventsPage requests a page of the events matching the filter, most recent
+// first.
+//
+// NOTE: the go-fastly GetAPIEvents function doesn't send a sort order, so the
+// API returns the oldest events first, and it follows the links to the next
+// pages itself while ignoring the errors of those requests.
+func getEventsPage(conn *gofastly.Client, i *gofastly.GetAPIEventsFilterInput) (*gofastly.GetAPIEventsResponse, error) {
+	params := map[string]string{
+		"sort":         "-created_at",
+		"page[number]": strconv.Itoa(i.PageNumber),
+	}
+	if i.MaxResults != 0 {
+		params["page[size]"] = strconv.Itoa(i.MaxResults)
+	}
+	for k, v := range map[string]string{
+		"filter[customer_id]": i.CustomerID,
+		"filter[service_id]":  i.ServiceID,
+		"filter[event_type]":  i.EventType,
+		"filter[user_id]":     i.UserID,
+	} {
+		if v != "" {
+			params[k] = v
+		}
+	}
+
+	resp, err := conn.Get("/events", &gofastly.RequestOptions{Params: params})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var page struct {
+		Data []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				CustomerID  string                 `json:"customer_id"`
+				Description string                 `json:"description"`
+				EventType   string                 `json:"event_type"`
+				IP          string                 `json:"ip"`
+				Metadata    map[string]interface{} `json:"metadata"`
+				ServiceID   string                 `json:"service_id"`
+				UserID      string                 `json:"user_id"`
+				CreatedAt   *time.Time             `json:"created_at"`
+				Admin       bool                   `json:"admin"`
+			} `json:"attributes"`
+		} `json:"data"`
+		Links gofastly.EventsPaginationInfo `json:"links"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+
+	result := &gofastly.GetAPIEventsResponse{Links: page.Links}
+	for _, e := range page.Data {
+		result.Events = append(result.Events, &gofastly.Event{
+			ID:          e.ID,
+			CustomerID:  e.Attributes.CustomerID,
+			Description: e.Attributes.Description,
+			EventType:   e.Attributes.EventType,
+			IP:          e.Attributes.IP,
+			Metadata:    e.Attributes.Metadata,
+			ServiceID:   e.Attributes.ServiceID,
+			UserID:      e.Attributes.UserID,
+			CreatedAt:   e.Attributes.CreatedAt,
+			Admin:       e.Attributes.Admin,
+		})
+	}
+	return result, nil
+}
+
+func flattenEvents(events []*gofastly.Event) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, e := range events {
+		result = append(result, map[string]interface{}{
+			"id":          e.ID,
+			"event_type":  e.EventType,
+			"description": e.Description,
+			"service_id":  e.ServiceID,
+			"customer_id": e.CustomerID,
+			"user_id":     e.UserID,
+			"ip":          e.IP,
+			"admin":       e.Admin,
+			"metadata":    flattenEventMetadata(e.Metadata),
+			"created_at":  formatOptionalTime(e.CreatedAt),
+		})
+	}
+	return result
+}
+
+// flattenEventMetadata converts the metadata of an event to a map of strings,
+// JSON encoding the values which aren't strings.
+func flattenEventMetadata(metadata map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		switch v := v.(type) {
+		case nil:
+			continue
+		case string:
+			result[k] = v
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				result[k] = fmt.Sprint(v)
+				continue
+			}
+			result[k] = string(b)
+		}
+	}
+	return result
+}
@@ -0,0 +1,199 @@
+package fastly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestListEvents(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("filter[service_id]") != "123" || q.Get("page[size]") != "2" || q.Get("sort") != "-created_at" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch q.Get("page[number]") {
+		case "1":
+			fmt.Fprintf(w, `{
+  "data": [
+    {"id": "1", "type": "event", "attributes": {"event_type": "version.activate", "service_id": "123", "user_id": "u1", "metadata": {"version": 1}, "created_at": "2021-01-01T00:00:00Z"}},
+    {"id": "3", "type": "event", "attributes": {"event_type": "version.activate", "service_id": "123", "user_id": "u2", "metadata": {"version": 3}, "created_at": "2021-03-01T00:00:00Z"}}
+  ],
+  "links": {"next": "%s/events?page[number]=2"}
+}`, server.URL)
+		case "2":
+			fmt.Fprint(w, `{
+  "data": [
+    {"id": "2", "type": "event", "attributes": {"event_type": "version.activate", "service_id": "123", "user_id": "u1", "metadata": {"version": 2}, "created_at": "2021-02-01T00:00:00Z"}}
+  ],
+  "links": {}
+}`)
+		default:
+			t.Errorf("unexpected page: %s", q.Get("page[number]"))
+		}
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := listEvents(conn, &gofastly.GetAPIEventsFilterInput{
+		ServiceID:  "123",
+		MaxResults: 2,
+	}, DefaultMaxEvents)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	if expected := []string{"3", "2", "1"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected events %#v, got %#v", expected, ids)
+	}
+}
+
+func TestListEvents_maxEvents(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page[number]"))
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		fmt.Fprint(w, `{
+  "data": [
+    {"id": "1", "type": "event", "attributes": {"created_at": "2021-01-01T00:00:00Z"}},
+    {"id": "2", "type": "event", "attributes": {"created_at": "2021-02-01T00:00:00Z"}}
+  ],
+  "links": {"next": "https://api.fastly.com/events?page[number]=2"}
+}`)
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := listEvents(conn, &gofastly.GetAPIEventsFilterInput{MaxResults: 2}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := []string{"1", "2"}; !reflect.DeepEqual(pages, expected) {
+		t.Errorf("expected pages %#v, got %#v", expected, pages)
+	}
+	if len(events) != 3 {
+		t.Errorf("expected 3 events, got %d", len(events))
+	}
+}
+
+func TestFlattenEvents(t *testing.T) {
+	createdAt := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := []*gofastly.Event{
+		{
+			ID:          "abc",
+			EventType:   "version.activate",
+			Description: "Version 3 was activated",
+			ServiceID:   "123",
+			CustomerID:  "456",
+			UserID:      "789",
+			IP:          "192.0.2.1",
+			Metadata: map[string]interface{}{
+				"version": float64(3),
+				"comment": "deploy",
+				"unset":   nil,
+			},
+			CreatedAt: &createdAt,
+		},
+	}
+
+	expected := []map[string]interface{}{
+		{
+			"id":          "abc",
+			"event_type":  "version.activate",
+			"description": "Version 3 was activated",
+			"service_id":  "123",
+			"customer_id": "456",
+			"user_id":     "789",
+			"ip":          "192.0.2.1",
+			"admin":       false,
+			"metadata": map[string]interface{}{
+				"version": "3",
+				"comment": "deploy",
+			},
+			"created_at": "2021-03-01T00:00:00Z",
+		},
+	}
+
+	if out := flattenEvents(events); !reflect.DeepEqual(out, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, out)
+	}
+}
+
+func TestAccFastlyDataSourceEvents_basic(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+	dataSourceName := "data.fastly_events.activations"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceV1Config(name, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+				),
+			},
+			{
+				Config: testAccFastlyDataSourceEventsConfig(name, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "events.0.event_type", "version.activate"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "events.0.service_id", "fastly_service_v1.foo", "id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "events.0.user_id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "events.0.created_at"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFastlyDataSourceEventsConfig(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  force_destroy = true
+}
+
+data "fastly_events" "activations" {
+  service_id = fastly_service_v1.foo.id
+  event_type = "version.activate"
+}
+`, name, domain)
+}
@@ -0,0 +1,200 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceFastlyService() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyServiceRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "ID of the service to look up. Conflicts with `name`.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "Name of the service to look up. Conflicts with `id`.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: fmt.Sprintf("The type of the service, `%s` or `%s`.", ServiceTypeVCL, ServiceTypeCompute),
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The description of the service.",
+			},
+			"customer_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the customer account the service belongs to.",
+			},
+			"active_version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The currently active version of the service. `0` when no version is active.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp (GMT) when the service was created.",
+			},
+			"updated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp (GMT) when the service was last updated.",
+			},
+			"versions": serviceVersionsSchema(),
+		},
+	}
+}
+
+// serviceVersionsSchema returns the schema of the versions of a service, which
+// is shared by the fastly_service and fastly_services data sources.
+func serviceVersionsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The versions of the service, ordered by number.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"number": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "The number of the version.",
+				},
+				"comment": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The description of the version.",
+				},
+				"active": {
+					Type:        schema.TypeBool,
+					Computed:    true,
+					Description: "Whether the version is the active version.",
+				},
+				"locked": {
+					Type:        schema.TypeBool,
+					Computed:    true,
+					Description: "Whether the version is locked and can no longer be modified.",
+				},
+				"staging": {
+					Type:        schema.TypeBool,
+					Computed:    true,
+					Description: "Whether the version is deployed to the staging network.",
+				},
+				"testing": {
+					Type:        schema.TypeBool,
+					Computed:    true,
+					Description: "Whether the version is in testing.",
+				},
+				"deployed": {
+					Type:        schema.TypeBool,
+					Computed:    true,
+					Description: "Whether the version has been deployed.",
+				},
+				"created_at": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Timestamp (GMT) when the version was created.",
+				},
+				"updated_at": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Timestamp (GMT) when the version was last updated.",
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFastlyServiceRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	serviceID := d.Get("id").(string)
+
+	if v, ok := d.GetOk("name"); ok && serviceID == "" {
+		log.Printf("[DEBUG] Searching for service named (%s)", v.(string))
+		s, err := conn.SearchService(&gofastly.SearchServiceInput{
+			Name: v.(string),
+		})
+		if err != nil {
+			return diag.Errorf("Error looking up service named (%s): %s", v.(string), err)
+		}
+		serviceID = s.ID
+	}
+
+	s, err := conn.GetServiceDetails(&gofastly.GetServiceInput{
+		ID: serviceID,
+	})
+	if err != nil {
+		return diag.Errorf("Error looking up service (%s): %s", serviceID, err)
+	}
+
+	d.SetId(s.ID)
+	if err := d.Set("name", s.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("type", s.Type); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("comment", s.Comment); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("customer_id", s.CustomerID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("active_version", s.ActiveVersion.Number); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("created_at", formatOptionalTime(s.CreatedAt)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("updated_at", formatOptionalTime(s.UpdatedAt)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("versions", flattenServiceVersions(s.Versions)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenServiceVersions(versions []*gofastly.Version) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, v := range versions {
+		result = append(result, map[string]interface{}{
+			"number":     v.Number,
+			"comment":    v.Comment,
+			"active":     v.Active,
+			"locked":     v.Locked,
+			"staging":    v.Staging,
+			"testing":    v.Testing,
+			"deployed":   v.Deployed,
+			"created_at": formatOptionalTime(v.CreatedAt),
+			"updated_at": formatOptionalTime(v.UpdatedAt),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i]["number"].(int) < result[j]["number"].(int)
+	})
+
+	return result
+}
@@ -0,0 +1,76 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceFastlyServiceGeneratedVCL() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyServiceGeneratedVCLRead,
+
+		Schema: map[string]*schema.Schema{
+			"service_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the service to get the generated VCL of.",
+			},
+			"version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The version of the service to get the generated VCL of. Defaults to the active version of the service.",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The VCL generated by Fastly from the configuration of the service version, including any custom VCL, snippets and conditions.",
+			},
+		},
+	}
+}
+
+func dataSourceFastlyServiceGeneratedVCLRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	serviceID := d.Get("service_id").(string)
+	version := d.Get("version").(int)
+
+	if version == 0 {
+		s, err := conn.GetServiceDetails(&gofastly.GetServiceInput{
+			ID: serviceID,
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		version = s.ActiveVersion.Number
+		if version == 0 {
+			return diag.Errorf("Service (%s) has no active version. Please set version and try again.", serviceID)
+		}
+	}
+
+	vcl, err := conn.GetGeneratedVCL(&gofastly.GetGeneratedVCLInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return diag.Errorf("Error looking up generated VCL for (%s), version (%d): %s", serviceID, version, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%d", serviceID, version))
+	if err := d.Set("version", version); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("content", vcl.Content); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
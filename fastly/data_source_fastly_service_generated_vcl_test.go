@@ -0,0 +1,62 @@
+package fastly
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFastlyDataSourceServiceGeneratedVCL_basic(t *testing.T) {
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domainName := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+	dataSourceName := "data.fastly_service_generated_vcl.active"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFastlyDataSourceServiceGeneratedVCLConfig(name, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "version", "fastly_service_v1.foo", "active_version"),
+					resource.TestMatchResourceAttr(dataSourceName, "content", regexp.MustCompile(`sub vcl_recv`)),
+					resource.TestMatchResourceAttr(dataSourceName, "content", regexp.MustCompile(`set req\.http\.tf-test = "generated"`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccFastlyDataSourceServiceGeneratedVCLConfig(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  snippet {
+    name    = "recv_header"
+    type    = "recv"
+    content = "set req.http.tf-test = \"generated\";"
+  }
+
+  force_destroy = true
+}
+
+data "fastly_service_generated_vcl" "active" {
+  service_id = fastly_service_v1.foo.id
+  version    = fastly_service_v1.foo.active_version
+}`, name, domain)
+}
@@ -0,0 +1,177 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/fastly/terraform-provider-fastly/fastly/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	statsByMinute = "minute"
+	statsByHour   = "hour"
+	statsByDay    = "day"
+)
+
+// statsQuerySchema returns the attributes of the period and region queried
+// from the historical stats API, shared by the stats and usage data sources.
+func statsQuerySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"from": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The start of the period, as a Unix timestamp, an ISO 8601 date or a relative time such as `2 days ago`. Defaults to the default of the API for the sampling rate.",
+		},
+		"to": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The end of the period, in the same formats as `from`. Defaults to now.",
+		},
+		"by": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{statsByMinute, statsByHour, statsByDay}, false),
+			Description:  fmt.Sprintf("The sampling rate of the stats, one of `%s`, `%s` or `%s`. Defaults to `%s`.", statsByMinute, statsByHour, statsByDay, statsByDay),
+		},
+		"region": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Limit the stats to a region, e.g. `usa` or `europe`. Defaults to every region.",
+		},
+	}
+}
+
+func dataSourceFastlyServiceStats() *schema.Resource {
+	s := statsQuerySchema()
+	s["service_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "The ID of the service.",
+	}
+	s["requests"] = statsCountSchema("Number of requests processed.")
+	s["hits"] = statsCountSchema("Number of cache hits.")
+	s["miss"] = statsCountSchema("Number of cache misses.")
+	s["pass"] = statsCountSchema("Number of requests passed through to the origin without being cached.")
+	s["synth"] = statsCountSchema("Number of requests which returned a synthetic response.")
+	s["errors"] = statsCountSchema("Number of cache errors.")
+	s["bandwidth"] = statsCountSchema("Total bytes delivered, body and header.")
+	s["resp_body_bytes"] = statsCountSchema("Total body bytes delivered.")
+	s["status_2xx"] = statsCountSchema("Number of success status codes delivered.")
+	s["status_3xx"] = statsCountSchema("Number of redirection status codes delivered.")
+	s["status_4xx"] = statsCountSchema("Number of client error status codes delivered.")
+	s["status_5xx"] = statsCountSchema("Number of server error status codes delivered.")
+	s["hit_ratio"] = statsHitRatioSchema("Ratio of cache hits to cache hits and misses over the period, between 0 and 1.")
+
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyServiceStatsRead,
+		Schema:      s,
+	}
+}
+
+func statsCountSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: description,
+	}
+}
+
+func statsHitRatioSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeFloat,
+		Computed:    true,
+		Description: description,
+	}
+}
+
+func dataSourceFastlyServiceStatsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	i := &gofastly.GetStatsInput{
+		Service: d.Get("service_id").(string),
+		From:    d.Get("from").(string),
+		To:      d.Get("to").(string),
+		By:      d.Get("by").(string),
+		Region:  d.Get("region").(string),
+	}
+
+	log.Printf("[DEBUG] Getting stats of service (%s)", i.Service)
+
+	stats, err := getServiceStats(conn, i)
+	if err != nil {
+		return diag.Errorf("Error getting stats of service (%s): %s", i.Service, err)
+	}
+
+	d.SetId(hashcode.Strings([]string{i.Service, i.From, i.To, i.By, i.Region}))
+	for k, v := range flattenServiceStats(stats) {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// getServiceStats returns the sum of the stats of every sample of the period.
+func getServiceStats(conn *gofastly.Client, i *gofastly.GetStatsInput) (*gofastly.Stats, error) {
+	resp, err := conn.GetStats(i)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+
+	return sumStats(resp.Data), nil
+}
+
+// sumStats returns the sum of the given samples, with the hit ratio computed
+// from the summed hits and misses.
+func sumStats(samples []*gofastly.Stats) *gofastly.Stats {
+	var sum gofastly.Stats
+	for _, s := range samples {
+		if s == nil {
+			continue
+		}
+		sum.Requests += s.Requests
+		sum.Hits += s.Hits
+		sum.Miss += s.Miss
+		sum.Pass += s.Pass
+		sum.Synth += s.Synth
+		sum.Errors += s.Errors
+		sum.Bandwidth += s.Bandwidth
+		sum.ResponseBodyBytes += s.ResponseBodyBytes
+		sum.Status2xx += s.Status2xx
+		sum.Status3xx += s.Status3xx
+		sum.Status4xx += s.Status4xx
+		sum.Status5xx += s.Status5xx
+	}
+	if sum.Hits+sum.Miss > 0 {
+		sum.HitRatio = float64(sum.Hits) / float64(sum.Hits+sum.Miss)
+	}
+
+	return &sum
+}
+
+func flattenServiceStats(s *gofastly.Stats) map[string]interface{} {
+	return map[string]interface{}{
+		"requests":        int(s.Requests),
+		"hits":            int(s.Hits),
+		"miss":            int(s.Miss),
+		"pass":            int(s.Pass),
+		"synth":           int(s.Synth),
+		"errors":          int(s.Errors),
+		"bandwidth":       int(s.Bandwidth),
+		"resp_body_bytes": int(s.ResponseBodyBytes),
+		"status_2xx":      int(s.Status2xx),
+		"status_3xx":      int(s.Status3xx),
+		"status_4xx":      int(s.Status4xx),
+		"status_5xx":      int(s.Status5xx),
+		"hit_ratio":       s.HitRatio,
+	}
+}
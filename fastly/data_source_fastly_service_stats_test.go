@@ -0,0 +1,130 @@
+package fastly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestGetServiceStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats/service/123" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("by") != "hour" || q.Get("region") != "europe" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+  "status": "success",
+  "data": [
+    {"requests": 100, "hits": 60, "miss": 20, "pass": 20, "errors": 1, "bandwidth": 1000, "status_2xx": 90, "status_5xx": 1},
+    {"requests": 50, "hits": 40, "miss": 0, "pass": 10, "errors": 2, "bandwidth": 500, "status_2xx": 45, "status_4xx": 5}
+  ]
+}`)
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := getServiceStats(conn, &gofastly.GetStatsInput{
+		Service: "123",
+		By:      "hour",
+		Region:  "europe",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"requests":        150,
+		"hits":            100,
+		"miss":            20,
+		"pass":            30,
+		"synth":           0,
+		"errors":          3,
+		"bandwidth":       1500,
+		"resp_body_bytes": 0,
+		"status_2xx":      135,
+		"status_3xx":      0,
+		"status_4xx":      5,
+		"status_5xx":      1,
+		"hit_ratio":       100.0 / 120.0,
+	}
+	if out := flattenServiceStats(stats); !reflect.DeepEqual(out, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, out)
+	}
+}
+
+func TestGetServiceStats_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status": "error", "msg": "invalid from"}`)
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := getServiceStats(conn, &gofastly.GetStatsInput{Service: "123", From: "nope"}); err == nil || err.Error() != "invalid from" {
+		t.Errorf("expected the message of the API as error, got %v", err)
+	}
+}
+
+func TestAccFastlyDataSourceServiceStats_basic(t *testing.T) {
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+	dataSourceName := "data.fastly_service_stats.foo"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFastlyDataSourceServiceStatsConfig(name, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "requests", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "hit_ratio", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFastlyDataSourceServiceStatsConfig(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  force_destroy = true
+}
+
+data "fastly_service_stats" "foo" {
+  service_id = fastly_service_v1.foo.id
+  from       = "1 day ago"
+  by         = "hour"
+}
+`, name, domain)
+}
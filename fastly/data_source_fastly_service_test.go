@@ -0,0 +1,125 @@
+package fastly
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFlattenServiceVersions(t *testing.T) {
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		remote []*gofastly.Version
+		local  []map[string]interface{}
+	}{
+		{
+			remote: []*gofastly.Version{
+				{
+					Number:    2,
+					Comment:   "staged",
+					Locked:    true,
+					Staging:   true,
+					CreatedAt: &createdAt,
+				},
+				{
+					Number:   1,
+					Active:   true,
+					Locked:   true,
+					Deployed: true,
+				},
+			},
+			local: []map[string]interface{}{
+				{
+					"number":     1,
+					"comment":    "",
+					"active":     true,
+					"locked":     true,
+					"staging":    false,
+					"testing":    false,
+					"deployed":   true,
+					"created_at": "",
+					"updated_at": "",
+				},
+				{
+					"number":     2,
+					"comment":    "staged",
+					"active":     false,
+					"locked":     true,
+					"staging":    true,
+					"testing":    false,
+					"deployed":   false,
+					"created_at": "2021-01-02T03:04:05Z",
+					"updated_at": "",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		out := flattenServiceVersions(c.remote)
+		if !reflect.DeepEqual(out, c.local) {
+			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
+		}
+	}
+}
+
+func TestAccFastlyDataSourceService_basic(t *testing.T) {
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domainName := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFastlyDataSourceServiceConfig(name, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.fastly_service.by_name", "id", "fastly_service_v1.foo", "id"),
+					resource.TestCheckResourceAttrPair("data.fastly_service.by_name", "active_version", "fastly_service_v1.foo", "active_version"),
+					resource.TestCheckResourceAttr("data.fastly_service.by_name", "type", ServiceTypeVCL),
+					resource.TestCheckResourceAttr("data.fastly_service.by_name", "comment", "Managed by Terraform"),
+					resource.TestCheckResourceAttr("data.fastly_service.by_name", "versions.#", "1"),
+					resource.TestCheckResourceAttr("data.fastly_service.by_name", "versions.0.active", "true"),
+					resource.TestCheckResourceAttr("data.fastly_service.by_name", "versions.0.locked", "true"),
+					resource.TestCheckResourceAttr("data.fastly_service.by_id", "name", name),
+				),
+			},
+		},
+	})
+}
+
+func testAccFastlyDataSourceServiceConfig(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  force_destroy = true
+}
+
+data "fastly_service" "by_name" {
+  name = fastly_service_v1.foo.name
+
+  depends_on = [fastly_service_v1.foo]
+}
+
+data "fastly_service" "by_id" {
+  id = fastly_service_v1.foo.id
+}`, name, domain)
+}
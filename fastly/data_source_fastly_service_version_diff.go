@@ -0,0 +1,143 @@
+package fastly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	versionDiffFormatText       = "text"
+	versionDiffFormatHTML       = "html"
+	versionDiffFormatHTMLSimple = "html_simple"
+)
+
+func dataSourceFastlyServiceVersionDiff() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyServiceVersionDiffRead,
+
+		Schema: map[string]*schema.Schema{
+			"service_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the service to diff.",
+			},
+			"from_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The version to diff from. Defaults to the active version of the service.",
+			},
+			"to_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The version to diff to. Defaults to the latest version of the service, e.g. the draft cloned by a `fastly_service_v1` with `activate = false`.",
+			},
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      versionDiffFormatText,
+				ValidateFunc: validation.StringInSlice([]string{versionDiffFormatText, versionDiffFormatHTML, versionDiffFormatHTMLSimple}, false),
+				Description:  fmt.Sprintf("The format of the diff. One of `%s`, `%s` or `%s`. Default `%s`.", versionDiffFormatText, versionDiffFormatHTML, versionDiffFormatHTMLSimple, versionDiffFormatText),
+			},
+			"diff": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The differences between the generated configuration of the two versions.",
+			},
+		},
+	}
+}
+
+func dataSourceFastlyServiceVersionDiffRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	serviceID := d.Get("service_id").(string)
+	from := d.Get("from_version").(int)
+	to := d.Get("to_version").(int)
+
+	if from == 0 || to == 0 {
+		s, err := conn.GetServiceDetails(&gofastly.GetServiceInput{
+			ID: serviceID,
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if from == 0 {
+			from = s.ActiveVersion.Number
+		}
+		if to == 0 {
+			to = s.Version.Number
+		}
+	}
+
+	if from == 0 {
+		return diag.Errorf("Service (%s) has no active version. Please set from_version and try again.", serviceID)
+	}
+
+	diff, err := getVersionDiff(conn, &gofastly.GetDiffInput{
+		ServiceID: serviceID,
+		From:      from,
+		To:        to,
+		Format:    d.Get("format").(string),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%d/%d", serviceID, diff.From, diff.To))
+	if err := d.Set("from_version", diff.From); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("to_version", diff.To); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("diff", diff.Diff); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// getVersionDiff requests the diff between two service versions.
+//
+// NOTE: the go-fastly GetDiff function doesn't send the Format field to the
+// API, which means only the default text format could be requested through it.
+func getVersionDiff(conn *gofastly.Client, i *gofastly.GetDiffInput) (*gofastly.Diff, error) {
+	if i.ServiceID == "" {
+		return nil, gofastly.ErrMissingServiceID
+	}
+	if i.From == 0 {
+		return nil, gofastly.ErrMissingFrom
+	}
+	if i.To == 0 {
+		return nil, gofastly.ErrMissingTo
+	}
+
+	ro := &gofastly.RequestOptions{}
+	if i.Format != "" {
+		ro.Params = map[string]string{"format": i.Format}
+	}
+
+	path := fmt.Sprintf("/service/%s/diff/from/%d/to/%d", i.ServiceID, i.From, i.To)
+	resp, err := conn.Get(path, ro)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var diff gofastly.Diff
+	if err := json.NewDecoder(resp.Body).Decode(&diff); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
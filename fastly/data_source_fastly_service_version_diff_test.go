@@ -0,0 +1,144 @@
+package fastly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestGetVersionDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/123/diff/from/1/to/2" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		format := r.URL.Query().Get("format")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"format": %q, "from": 1, "to": 2, "diff": "-a\n+b"}`, format)
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{versionDiffFormatText, versionDiffFormatHTML, versionDiffFormatHTMLSimple} {
+		diff, err := getVersionDiff(conn, &gofastly.GetDiffInput{
+			ServiceID: "123",
+			From:      1,
+			To:        2,
+			Format:    format,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := &gofastly.Diff{
+			Format: format,
+			From:   1,
+			To:     2,
+			Diff:   "-a\n+b",
+		}
+		if *diff != *expected {
+			t.Errorf("bad diff, expected (%#v), got (%#v)", expected, diff)
+		}
+	}
+}
+
+func TestGetVersionDiff_missingInput(t *testing.T) {
+	conn, err := gofastly.NewClientForEndpoint("key", "http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		input    *gofastly.GetDiffInput
+		expected error
+	}{
+		"missing service ID": {
+			input:    &gofastly.GetDiffInput{From: 1, To: 2},
+			expected: gofastly.ErrMissingServiceID,
+		},
+		"missing from": {
+			input:    &gofastly.GetDiffInput{ServiceID: "123", To: 2},
+			expected: gofastly.ErrMissingFrom,
+		},
+		"missing to": {
+			input:    &gofastly.GetDiffInput{ServiceID: "123", From: 1},
+			expected: gofastly.ErrMissingTo,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := getVersionDiff(conn, c.input); err != c.expected {
+				t.Errorf("expected error (%v), got (%v)", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestAccFastlyDataSourceServiceVersionDiff_basic(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domainName1 := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+	domainName2 := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+	dataSourceName := "data.fastly_service_version_diff.pending"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceV1Config(name, domainName1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+				),
+			},
+			{
+				Config: testAccFastlyDataSourceServiceVersionDiffConfig(name, domainName2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fastly_service_v1.foo", "active_version", "1"),
+					resource.TestCheckResourceAttr("fastly_service_v1.foo", "cloned_version", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "from_version", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "to_version", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "format", "text"),
+					resource.TestMatchResourceAttr(dataSourceName, "diff", regexp.MustCompile(regexp.QuoteMeta(domainName2))),
+				),
+			},
+		},
+	})
+}
+
+func testAccFastlyDataSourceServiceVersionDiffConfig(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  activate      = false
+  force_destroy = true
+}
+
+data "fastly_service_version_diff" "pending" {
+  service_id   = fastly_service_v1.foo.id
+  from_version = fastly_service_v1.foo.active_version
+  to_version   = fastly_service_v1.foo.cloned_version
+}`, name, domain)
+}
@@ -0,0 +1,164 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/fastly/terraform-provider-fastly/fastly/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceFastlyServices() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyServicesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "A regular expression the name of the services must match.",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{ServiceTypeVCL, ServiceTypeCompute}, false),
+				Description:  fmt.Sprintf("The type of the services, `%s` or `%s`.", ServiceTypeVCL, ServiceTypeCompute),
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the matching services, ordered by name.",
+			},
+			"services": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching services, ordered by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the service.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the service.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: fmt.Sprintf("The type of the service, `%s` or `%s`.", ServiceTypeVCL, ServiceTypeCompute),
+						},
+						"comment": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The description of the service.",
+						},
+						"customer_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the customer account the service belongs to.",
+						},
+						"active_version": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The currently active version of the service. `0` when no version is active.",
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Timestamp (GMT) when the service was created.",
+						},
+						"updated_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Timestamp (GMT) when the service was last updated.",
+						},
+						"versions": serviceVersionsSchema(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFastlyServicesRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	log.Printf("[DEBUG] Listing services")
+
+	services, err := conn.ListServices(&gofastly.ListServicesInput{})
+	if err != nil {
+		return diag.Errorf("Error listing services: %s", err)
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	services = filterServices(services, nameRegex, d.Get("type").(string))
+
+	var ids []string
+	for _, s := range services {
+		ids = append(ids, s.ID)
+	}
+
+	d.SetId(hashcode.Strings(ids))
+	if err := d.Set("ids", ids); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("services", flattenServices(services)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// filterServices returns the services matching the name regular expression
+// and type, ordered by name. Empty filters match every service.
+func filterServices(services []*gofastly.Service, nameRegex *regexp.Regexp, serviceType string) []*gofastly.Service {
+	var result []*gofastly.Service
+	for _, s := range services {
+		if nameRegex != nil && !nameRegex.MatchString(s.Name) {
+			continue
+		}
+		if serviceType != "" && s.Type != serviceType {
+			continue
+		}
+		result = append(result, s)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func flattenServices(services []*gofastly.Service) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, s := range services {
+		result = append(result, map[string]interface{}{
+			"id":             s.ID,
+			"name":           s.Name,
+			"type":           s.Type,
+			"comment":        s.Comment,
+			"customer_id":    s.CustomerID,
+			"active_version": int(s.ActiveVersion),
+			"created_at":     formatOptionalTime(s.CreatedAt),
+			"updated_at":     formatOptionalTime(s.UpdatedAt),
+			"versions":       flattenServiceVersions(s.Versions),
+		})
+	}
+	return result
+}
@@ -0,0 +1,168 @@
+package fastly
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFilterServices(t *testing.T) {
+	services := []*gofastly.Service{
+		{ID: "3", Name: "www", Type: ServiceTypeVCL},
+		{ID: "1", Name: "api", Type: ServiceTypeVCL},
+		{ID: "2", Name: "api-compute", Type: ServiceTypeCompute},
+	}
+
+	cases := map[string]struct {
+		nameRegex   *regexp.Regexp
+		serviceType string
+		expected    []string
+	}{
+		"no filters": {
+			expected: []string{"1", "2", "3"},
+		},
+		"name regex": {
+			nameRegex: regexp.MustCompile("^api"),
+			expected:  []string{"1", "2"},
+		},
+		"type": {
+			serviceType: ServiceTypeVCL,
+			expected:    []string{"1", "3"},
+		},
+		"name regex and type": {
+			nameRegex:   regexp.MustCompile("^api"),
+			serviceType: ServiceTypeCompute,
+			expected:    []string{"2"},
+		},
+		"no match": {
+			nameRegex: regexp.MustCompile("^nope$"),
+			expected:  nil,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var ids []string
+			for _, s := range filterServices(services, c.nameRegex, c.serviceType) {
+				ids = append(ids, s.ID)
+			}
+			if !reflect.DeepEqual(ids, c.expected) {
+				t.Errorf("expected %#v, got %#v", c.expected, ids)
+			}
+		})
+	}
+}
+
+func TestFlattenServices(t *testing.T) {
+	cases := []struct {
+		remote []*gofastly.Service
+		local  []map[string]interface{}
+	}{
+		{
+			remote: []*gofastly.Service{
+				{
+					ID:            "123",
+					Name:          "www",
+					Type:          ServiceTypeVCL,
+					Comment:       "Managed by Terraform",
+					CustomerID:    "456",
+					ActiveVersion: 1,
+					Versions: []*gofastly.Version{
+						{Number: 1, Active: true, Locked: true},
+					},
+				},
+			},
+			local: []map[string]interface{}{
+				{
+					"id":             "123",
+					"name":           "www",
+					"type":           ServiceTypeVCL,
+					"comment":        "Managed by Terraform",
+					"customer_id":    "456",
+					"active_version": 1,
+					"created_at":     "",
+					"updated_at":     "",
+					"versions": []map[string]interface{}{
+						{
+							"number":     1,
+							"comment":    "",
+							"active":     true,
+							"locked":     true,
+							"staging":    false,
+							"testing":    false,
+							"deployed":   false,
+							"created_at": "",
+							"updated_at": "",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		out := flattenServices(c.remote)
+		if !reflect.DeepEqual(out, c.local) {
+			t.Fatalf("Error matching:\nexpected: %#v\ngot: %#v", c.local, out)
+		}
+	}
+}
+
+func TestAccFastlyDataSourceServices_basic(t *testing.T) {
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domainName := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFastlyDataSourceServicesConfig(name, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fastly_services.some", "ids.#", "1"),
+					resource.TestCheckResourceAttrPair("data.fastly_services.some", "ids.0", "fastly_service_v1.foo", "id"),
+					resource.TestCheckResourceAttr("data.fastly_services.some", "services.0.name", name),
+					resource.TestCheckResourceAttr("data.fastly_services.some", "services.0.type", ServiceTypeVCL),
+					resource.TestCheckResourceAttrPair("data.fastly_services.some", "services.0.active_version", "fastly_service_v1.foo", "active_version"),
+					resource.TestCheckResourceAttr("data.fastly_services.some", "services.0.versions.#", "1"),
+					resource.TestCheckResourceAttr("data.fastly_services.none", "ids.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFastlyDataSourceServicesConfig(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  force_destroy = true
+}
+
+data "fastly_services" "some" {
+  name_regex = "^${fastly_service_v1.foo.name}$"
+  type       = "vcl"
+}
+
+data "fastly_services" "none" {
+  name_regex = "^${fastly_service_v1.foo.name}$"
+  type       = "wasm"
+}`, name, domain)
+}
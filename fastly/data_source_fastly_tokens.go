@@ -0,0 +1,129 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/fastly/terraform-provider-fastly/fastly/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceFastlyTokens() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyTokensRead,
+
+		Schema: map[string]*schema.Schema{
+			"customer_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of the customer account to list tokens for. Defaults to the account of the current user. Listing the tokens of a customer requires superuser permissions",
+			},
+			"tokens": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The tokens of the customer account.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the token.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the token.",
+						},
+						"user_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the user the token belongs to.",
+						},
+						"scope": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The scope of the token.",
+						},
+						"services": {
+							Type:        schema.TypeSet,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The service IDs the token is limited to.",
+						},
+						"ip": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The IP address of the client that created the token.",
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time-stamp (GMT) when the token was created.",
+						},
+						"last_used_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time-stamp (GMT) when the token was last used.",
+						},
+						"expires_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time-stamp (GMT) when the token will expire.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFastlyTokensRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	customerID := d.Get("customer_id").(string)
+	if customerID == "" {
+		user, err := conn.GetCurrentUser()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		customerID = user.CustomerID
+	}
+
+	tokens, err := conn.ListCustomerTokens(&gofastly.ListCustomerTokensInput{
+		CustomerID: customerID,
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", hashcode.String(customerID)))
+	if err := d.Set("customer_id", customerID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("tokens", flattenTokens(tokens)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenTokens(tokens []*gofastly.Token) []map[string]interface{} {
+	tl := make([]map[string]interface{}, 0, len(tokens))
+	for _, t := range tokens {
+		tl = append(tl, map[string]interface{}{
+			"id":           t.ID,
+			"name":         t.Name,
+			"user_id":      t.UserID,
+			"scope":        string(t.Scope),
+			"services":     t.Services,
+			"ip":           t.IP,
+			"created_at":   formatOptionalTime(t.CreatedAt),
+			"last_used_at": formatOptionalTime(t.LastUsedAt),
+			"expires_at":   formatOptionalTime(t.ExpiresAt),
+		})
+	}
+	return tl
+}
@@ -0,0 +1,71 @@
+package fastly
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFlattenTokens(t *testing.T) {
+	createdAt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		remote []*gofastly.Token
+		local  []map[string]interface{}
+	}{
+		{
+			remote: []*gofastly.Token{
+				{
+					ID:        "token-id",
+					Name:      "ci",
+					UserID:    "user-id",
+					Services:  []string{"service-id"},
+					Scope:     gofastly.PurgeAllScope,
+					IP:        "127.0.0.1",
+					CreatedAt: &createdAt,
+				},
+			},
+			local: []map[string]interface{}{
+				{
+					"id":           "token-id",
+					"name":         "ci",
+					"user_id":      "user-id",
+					"scope":        "purge_all",
+					"services":     []string{"service-id"},
+					"ip":           "127.0.0.1",
+					"created_at":   "2021-06-01T12:00:00Z",
+					"last_used_at": "",
+					"expires_at":   "",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		out := flattenTokens(c.remote)
+		if !reflect.DeepEqual(out, c.local) {
+			t.Fatalf("Error matching:\nexpected: %#v\n     got: %#v", c.local, out)
+		}
+	}
+}
+
+func TestAccFastlyDataSourceTokens(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFastlyAccDataSourceTokens,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.fastly_tokens.subject", "customer_id"),
+					resource.TestCheckResourceAttrSet("data.fastly_tokens.subject", "tokens.#"),
+				),
+			},
+		},
+	})
+}
+
+const testAccFastlyAccDataSourceTokens = `data "fastly_tokens" "subject" {}`
@@ -0,0 +1,215 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/fastly/terraform-provider-fastly/fastly/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceFastlyUsage() *schema.Resource {
+	s := statsQuerySchema()
+	s["service_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Limit the usage to a service. Defaults to the usage of every service of the account.",
+	}
+	s["requests"] = statsCountSchema("Number of requests processed in every region.")
+	s["bandwidth"] = statsCountSchema("Total bytes delivered in every region.")
+	s["errors"] = statsCountSchema("Number of cache errors in every region.")
+	s["hit_ratio"] = statsHitRatioSchema("Ratio of cache hits to cache hits and misses in every region, between 0 and 1.")
+	s["regions"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The usage per region, ordered by name.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The name of the region.",
+				},
+				"requests":  statsCountSchema("Number of requests processed in the region."),
+				"bandwidth": statsCountSchema("Total bytes delivered in the region."),
+				"errors":    statsCountSchema("Number of cache errors in the region."),
+				"hit_ratio": statsHitRatioSchema("Ratio of cache hits to cache hits and misses in the region, between 0 and 1."),
+			},
+		},
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceFastlyUsageRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceFastlyUsageRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	serviceID := d.Get("service_id").(string)
+	i := &gofastly.GetUsageInput{
+		From:   d.Get("from").(string),
+		To:     d.Get("to").(string),
+		By:     d.Get("by").(string),
+		Region: d.Get("region").(string),
+	}
+
+	log.Printf("[DEBUG] Getting usage")
+
+	usage, err := getUsage(conn, i, serviceID)
+	if err != nil {
+		return diag.Errorf("Error getting usage: %s", err)
+	}
+
+	// The usage API only reports requests and bandwidth, so the errors and
+	// hit ratio of each region are read from the stats API.
+	stats, err := getRegionStats(conn, i, serviceID, usage)
+	if err != nil {
+		return diag.Errorf("Error getting stats: %s", err)
+	}
+
+	var requests, bandwidth uint64
+	for _, u := range usage {
+		if u == nil {
+			continue
+		}
+		requests += u.Requests
+		bandwidth += u.Bandwidth
+	}
+	var samples []*gofastly.Stats
+	for _, s := range stats {
+		samples = append(samples, s)
+	}
+	total := sumStats(samples)
+
+	d.SetId(hashcode.Strings([]string{serviceID, i.From, i.To, i.By, i.Region}))
+	if err := d.Set("requests", int(requests)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("bandwidth", int(bandwidth)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("errors", int(total.Errors)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("hit_ratio", total.HitRatio); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("regions", flattenUsage(usage, stats)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// getUsage returns the usage per region, of the service when serviceID is not
+// empty and of every service otherwise.
+func getUsage(conn *gofastly.Client, i *gofastly.GetUsageInput, serviceID string) (gofastly.RegionsUsage, error) {
+	if serviceID == "" {
+		resp, err := conn.GetUsage(i)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Status != "success" {
+			return nil, fmt.Errorf("%s", resp.Message)
+		}
+		if resp.Data == nil {
+			return nil, nil
+		}
+		return *resp.Data, nil
+	}
+
+	resp, err := conn.GetUsageByService(i)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+
+	usage := make(gofastly.RegionsUsage)
+	if resp.Data == nil {
+		return usage, nil
+	}
+	for region, services := range *resp.Data {
+		if services == nil {
+			continue
+		}
+		if u, ok := (*services)[serviceID]; ok && u != nil {
+			usage[region] = u
+		}
+	}
+	return usage, nil
+}
+
+// getRegionStats returns the stats of each region of the usage, summed over
+// the period, of the service when serviceID is not empty and of every service
+// otherwise.
+func getRegionStats(conn *gofastly.Client, i *gofastly.GetUsageInput, serviceID string, usage gofastly.RegionsUsage) (map[string]*gofastly.Stats, error) {
+	stats := make(map[string]*gofastly.Stats, len(usage))
+	for region := range usage {
+		si := &gofastly.GetStatsInput{
+			Service: serviceID,
+			From:    i.From,
+			To:      i.To,
+			By:      i.By,
+			Region:  region,
+		}
+
+		if serviceID != "" {
+			s, err := getServiceStats(conn, si)
+			if err != nil {
+				return nil, err
+			}
+			stats[region] = s
+			continue
+		}
+
+		// Without a service, the stats API returns the samples of every
+		// service, keyed by service ID.
+		resp, err := conn.GetStatsField(si)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Status != "success" {
+			return nil, fmt.Errorf("%s", resp.Message)
+		}
+		var samples []*gofastly.Stats
+		for _, s := range resp.Data {
+			samples = append(samples, s...)
+		}
+		stats[region] = sumStats(samples)
+	}
+	return stats, nil
+}
+
+func flattenUsage(usage gofastly.RegionsUsage, stats map[string]*gofastly.Stats) []map[string]interface{} {
+	var result []map[string]interface{}
+	for region, u := range usage {
+		if u == nil {
+			continue
+		}
+		s, ok := stats[region]
+		if !ok {
+			s = &gofastly.Stats{}
+		}
+		result = append(result, map[string]interface{}{
+			"name":      region,
+			"requests":  int(u.Requests),
+			"bandwidth": int(u.Bandwidth),
+			"errors":    int(s.Errors),
+			"hit_ratio": s.HitRatio,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i]["name"].(string) < result[j]["name"].(string)
+	})
+
+	return result
+}
@@ -0,0 +1,116 @@
+package fastly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestGetUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/stats/usage":
+			fmt.Fprint(w, `{
+  "status": "success",
+  "data": {
+    "usa": {"requests": 100, "bandwidth": 1000},
+    "europe": {"requests": 50, "bandwidth": 500}
+  }
+}`)
+		case "/stats/usage_by_service":
+			fmt.Fprint(w, `{
+  "status": "success",
+  "data": {
+    "usa": {"123": {"requests": 60, "bandwidth": 600}, "456": {"requests": 40, "bandwidth": 400}},
+    "europe": {"456": {"requests": 50, "bandwidth": 500}},
+    "asia": {"123": {"requests": 5, "bandwidth": 50}}
+  }
+}`)
+		case "/stats":
+			data := map[string]string{
+				"usa":    `{"123": [{"hits": 8, "miss": 2, "errors": 1}], "456": [{"hits": 2, "miss": 8, "errors": 2}]}`,
+				"europe": `{"456": [{"hits": 3, "miss": 1, "errors": 0}]}`,
+			}[r.URL.Query().Get("region")]
+			fmt.Fprintf(w, `{"status": "success", "data": %s}`, data)
+		case "/stats/service/123":
+			data := map[string]string{
+				"usa":  `[{"hits": 6, "miss": 2, "errors": 1}, {"hits": 2, "miss": 0, "errors": 0}]`,
+				"asia": `[{"hits": 1, "miss": 1, "errors": 4}]`,
+			}[r.URL.Query().Get("region")]
+			fmt.Fprintf(w, `{"status": "success", "data": %s}`, data)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		serviceID string
+		expected  []map[string]interface{}
+	}{
+		"every service": {
+			expected: []map[string]interface{}{
+				{"name": "europe", "requests": 50, "bandwidth": 500, "errors": 0, "hit_ratio": 0.75},
+				{"name": "usa", "requests": 100, "bandwidth": 1000, "errors": 3, "hit_ratio": 0.5},
+			},
+		},
+		"one service": {
+			serviceID: "123",
+			expected: []map[string]interface{}{
+				{"name": "asia", "requests": 5, "bandwidth": 50, "errors": 4, "hit_ratio": 0.5},
+				{"name": "usa", "requests": 60, "bandwidth": 600, "errors": 1, "hit_ratio": 0.8},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			usage, err := getUsage(conn, &gofastly.GetUsageInput{}, c.serviceID)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			stats, err := getRegionStats(conn, &gofastly.GetUsageInput{}, c.serviceID, usage)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if out := flattenUsage(usage, stats); !reflect.DeepEqual(out, c.expected) {
+				t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", c.expected, out)
+			}
+		})
+	}
+}
+
+func TestAccFastlyDataSourceUsage_basic(t *testing.T) {
+	dataSourceName := "data.fastly_usage.account"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "fastly_usage" "account" {
+  from = "1 day ago"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "requests"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "bandwidth"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "errors"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "hit_ratio"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,277 @@
+package fastly
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	dictionaryItemsFormatJSON = "json"
+	dictionaryItemsFormatCSV  = "csv"
+	dictionaryItemsFormatYAML = "yaml"
+)
+
+// readDictionaryItemsFile reads the items of a dictionary from a file, in the
+// format given by its extension.
+func readDictionaryItemsFile(path string) (map[string]string, error) {
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = dictionaryItemsFormatJSON
+	case ".csv":
+		format = dictionaryItemsFormatCSV
+	case ".yaml", ".yml":
+		format = dictionaryItemsFormatYAML
+	default:
+		return nil, fmt.Errorf("unsupported format of %s, expected a .json, .csv, .yaml or .yml file", path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := decodeDictionaryItems(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", path, err)
+	}
+	return items, nil
+}
+
+// decodeDictionaryItems decodes the items of a dictionary from:
+//
+//   - a JSON object of keys to string, number or boolean values,
+//   - CSV rows of a key and a value, after an optional `key,value` header,
+//   - a YAML mapping of keys to scalar values.
+func decodeDictionaryItems(data []byte, format string) (map[string]string, error) {
+	items := make(map[string]string)
+
+	switch format {
+	case dictionaryItemsFormatJSON:
+		var m map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&m); err != nil {
+			return nil, err
+		}
+		for k, v := range m {
+			switch v := v.(type) {
+			case string:
+				items[k] = v
+			case json.Number, bool:
+				items[k] = fmt.Sprint(v)
+			default:
+				return nil, fmt.Errorf("the value of %q must be a string, a number or a boolean", k)
+			}
+		}
+
+	case dictionaryItemsFormatCSV:
+		r := csv.NewReader(bytes.NewReader(data))
+		r.FieldsPerRecord = 2
+		for line := 1; ; line++ {
+			record, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if line == 1 && record[0] == "key" && record[1] == "value" {
+				continue
+			}
+			if _, ok := items[record[0]]; ok {
+				return nil, fmt.Errorf("line %d: duplicate key %q", line, record[0])
+			}
+			items[record[0]] = record[1]
+		}
+
+	case dictionaryItemsFormatYAML:
+		if err := yaml.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+
+	return items, nil
+}
+
+// hashDictionaryItems returns a hash of the items of a dictionary, which
+// doesn't depend on the order of the items or on the file they were read from,
+// so that the hash of the remote items can be compared to the hash of a file.
+func hashDictionaryItems(items map[string]string) string {
+	keys := make([]string, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%d:%s%d:%s", len(k), k, len(items[k]), items[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// dictionaryItemsSummary counts the changes needed to sync a dictionary.
+type dictionaryItemsSummary struct {
+	added, changed, removed int
+}
+
+func (s dictionaryItemsSummary) String() string {
+	return fmt.Sprintf("%d added, %d changed, %d removed", s.added, s.changed, s.removed)
+}
+
+// diffDictionaryItems calls fn with each operation needed to turn the remote
+// items into the local ones, one at a time, so that the operations never need
+// to be held at once. The operations are ordered by key, deletions and
+// updates first. When additive, the remote items which aren't local are left
+// alone rather than deleted, unless their key is owned, having been declared
+// previously.
+func diffDictionaryItems(remote []*gofastly.DictionaryItem, local map[string]string, owned []string, additive bool, fn func(*gofastly.BatchDictionaryItem) error) (dictionaryItemsSummary, error) {
+	var summary dictionaryItemsSummary
+
+	previous := make(map[string]bool, len(owned))
+	for _, k := range owned {
+		previous[k] = true
+	}
+
+	seen := make(map[string]bool, len(remote))
+	for _, item := range remote {
+		seen[item.ItemKey] = true
+
+		value, ok := local[item.ItemKey]
+		switch {
+		case !ok && additive && !previous[item.ItemKey]:
+			continue
+		case !ok:
+			summary.removed++
+			if err := fn(&gofastly.BatchDictionaryItem{
+				Operation: gofastly.DeleteBatchOperation,
+				ItemKey:   item.ItemKey,
+			}); err != nil {
+				return summary, err
+			}
+		case value != item.ItemValue:
+			summary.changed++
+			if err := fn(&gofastly.BatchDictionaryItem{
+				Operation: gofastly.UpdateBatchOperation,
+				ItemKey:   item.ItemKey,
+				ItemValue: value,
+			}); err != nil {
+				return summary, err
+			}
+		}
+	}
+
+	var added []string
+	for k := range local {
+		if !seen[k] {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+
+	for _, k := range added {
+		summary.added++
+		if err := fn(&gofastly.BatchDictionaryItem{
+			Operation: gofastly.CreateBatchOperation,
+			ItemKey:   k,
+			ItemValue: local[k],
+		}); err != nil {
+			return summary, err
+		}
+	}
+
+	return summary, nil
+}
+
+// dictionaryItemsBatch sends the operations added to it to the API as soon as
+// they fill a batch.
+type dictionaryItemsBatch struct {
+	conn         *gofastly.Client
+	serviceID    string
+	dictionaryID string
+	items        []*gofastly.BatchDictionaryItem
+}
+
+func (b *dictionaryItemsBatch) add(item *gofastly.BatchDictionaryItem) error {
+	b.items = append(b.items, item)
+	if len(b.items) < gofastly.BatchModifyMaximumOperations {
+		return nil
+	}
+	return b.flush()
+}
+
+// flush sends the operations which don't fill a batch.
+func (b *dictionaryItemsBatch) flush() error {
+	if len(b.items) == 0 {
+		return nil
+	}
+	err := executeBatchDictionaryOperations(b.conn, b.serviceID, b.dictionaryID, b.items)
+	b.items = b.items[:0]
+	return err
+}
+
+// syncDictionaryItems lists the remote items of a dictionary, and streams the
+// operations needed to turn them into the local ones to the API.
+func syncDictionaryItems(conn *gofastly.Client, serviceID, dictionaryID string, local map[string]string, owned []string, additive bool) (dictionaryItemsSummary, error) {
+	remote, err := conn.ListDictionaryItems(&gofastly.ListDictionaryItemsInput{
+		ServiceID:    serviceID,
+		DictionaryID: dictionaryID,
+	})
+	if err != nil {
+		return dictionaryItemsSummary{}, err
+	}
+
+	b := &dictionaryItemsBatch{conn: conn, serviceID: serviceID, dictionaryID: dictionaryID}
+	summary, err := diffDictionaryItems(remote, local, owned, additive, b.add)
+	if err != nil {
+		return summary, err
+	}
+	return summary, b.flush()
+}
+
+// deleteDictionaryItems deletes the remote items of the given keys, leaving
+// the others alone.
+func deleteDictionaryItems(conn *gofastly.Client, serviceID, dictionaryID string, keys []string) error {
+	deleted := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		deleted[k] = true
+	}
+
+	remote, err := conn.ListDictionaryItems(&gofastly.ListDictionaryItemsInput{
+		ServiceID:    serviceID,
+		DictionaryID: dictionaryID,
+	})
+	if err != nil {
+		return err
+	}
+
+	b := &dictionaryItemsBatch{conn: conn, serviceID: serviceID, dictionaryID: dictionaryID}
+	for _, item := range remote {
+		if !deleted[item.ItemKey] {
+			continue
+		}
+		if err := b.add(&gofastly.BatchDictionaryItem{
+			Operation: gofastly.DeleteBatchOperation,
+			ItemKey:   item.ItemKey,
+		}); err != nil {
+			return err
+		}
+	}
+	return b.flush()
+}
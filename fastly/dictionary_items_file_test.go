@@ -0,0 +1,261 @@
+package fastly
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+)
+
+func TestDecodeDictionaryItems(t *testing.T) {
+	expected := map[string]string{
+		"/old":  "/new",
+		"count": "3",
+		"flag":  "true",
+	}
+
+	cases := []struct {
+		format string
+		data   string
+	}{
+		{dictionaryItemsFormatJSON, `{"/old": "/new", "count": 3, "flag": true}`},
+		{dictionaryItemsFormatCSV, "key,value\n/old,/new\ncount,3\nflag,true\n"},
+		{dictionaryItemsFormatCSV, "/old,/new\ncount,3\nflag,true\n"},
+		{dictionaryItemsFormatYAML, "/old: /new\ncount: 3\nflag: true\n"},
+	}
+
+	for _, c := range cases {
+		out, err := decodeDictionaryItems([]byte(c.data), c.format)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.format, err)
+			continue
+		}
+		if !reflect.DeepEqual(out, expected) {
+			t.Errorf("%s: Error matching:\nexpected: %#v\n     got: %#v", c.format, expected, out)
+		}
+	}
+}
+
+func TestDecodeDictionaryItems_invalid(t *testing.T) {
+	cases := []struct {
+		format string
+		data   string
+	}{
+		{dictionaryItemsFormatJSON, `["/old", "/new"]`},
+		{dictionaryItemsFormatJSON, `{"/old": {"to": "/new"}}`},
+		{dictionaryItemsFormatCSV, "/old,/new,301\n"},
+		{dictionaryItemsFormatCSV, "/old,/new\n/old,/other\n"},
+		{dictionaryItemsFormatYAML, "/old:\n  to: /new\n"},
+		{"xml", "<items/>"},
+	}
+
+	for _, c := range cases {
+		if _, err := decodeDictionaryItems([]byte(c.data), c.format); err == nil {
+			t.Errorf("%s: expected an error decoding %q", c.format, c.data)
+		}
+	}
+}
+
+func TestReadDictionaryItemsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dictionary-items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "items.yml")
+	if err := ioutil.WriteFile(path, []byte("key: value\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := readDictionaryItemsFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := map[string]string{"key": "value"}; !reflect.DeepEqual(items, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, items)
+	}
+
+	if _, err := readDictionaryItemsFile(filepath.Join(dir, "items.txt")); err == nil {
+		t.Errorf("expected an error reading a file with an unsupported extension")
+	}
+}
+
+func TestHashDictionaryItems(t *testing.T) {
+	a := hashDictionaryItems(map[string]string{"a": "1", "b": "2"})
+	b := hashDictionaryItems(map[string]string{"b": "2", "a": "1"})
+	if a != b {
+		t.Errorf("expected the hash not to depend on the order of the items, got %s and %s", a, b)
+	}
+
+	for _, items := range []map[string]string{
+		{"a": "1", "b": "3"},
+		{"a": "1b", "": "2"},
+		{"a": "1"},
+	} {
+		if hashDictionaryItems(items) == a {
+			t.Errorf("expected the hash of %#v to differ", items)
+		}
+	}
+}
+
+func TestDiffDictionaryItems(t *testing.T) {
+	remote := []*gofastly.DictionaryItem{
+		{ItemKey: "changed", ItemValue: "old"},
+		{ItemKey: "removed", ItemValue: "value"},
+		{ItemKey: "same", ItemValue: "value"},
+	}
+	local := map[string]string{
+		"added":   "value",
+		"changed": "new",
+		"same":    "value",
+	}
+
+	var ops []*gofastly.BatchDictionaryItem
+	summary, err := diffDictionaryItems(remote, local, nil, false, func(op *gofastly.BatchDictionaryItem) error {
+		ops = append(ops, op)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := "1 added, 1 changed, 1 removed"; summary.String() != expected {
+		t.Errorf("expected summary %q, got %q", expected, summary)
+	}
+
+	expected := []*gofastly.BatchDictionaryItem{
+		{Operation: gofastly.UpdateBatchOperation, ItemKey: "changed", ItemValue: "new"},
+		{Operation: gofastly.DeleteBatchOperation, ItemKey: "removed"},
+		{Operation: gofastly.CreateBatchOperation, ItemKey: "added", ItemValue: "value"},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, ops)
+	}
+}
+
+func TestDiffDictionaryItems_additive(t *testing.T) {
+	remote := []*gofastly.DictionaryItem{
+		{ItemKey: "external", ItemValue: "value"},
+		{ItemKey: "changed", ItemValue: "old"},
+		{ItemKey: "undeclared", ItemValue: "value"},
+	}
+	local := map[string]string{
+		"changed": "new",
+	}
+
+	var ops []*gofastly.BatchDictionaryItem
+	summary, err := diffDictionaryItems(remote, local, []string{"changed", "undeclared"}, true, func(op *gofastly.BatchDictionaryItem) error {
+		ops = append(ops, op)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := "0 added, 1 changed, 1 removed"; summary.String() != expected {
+		t.Errorf("expected summary %q, got %q", expected, summary)
+	}
+
+	expected := []*gofastly.BatchDictionaryItem{
+		{Operation: gofastly.UpdateBatchOperation, ItemKey: "changed", ItemValue: "new"},
+		{Operation: gofastly.DeleteBatchOperation, ItemKey: "undeclared"},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, ops)
+	}
+}
+
+func TestSyncDictionaryItems(t *testing.T) {
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/123/dictionary/456/items" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `[{"item_key": "removed", "item_value": "value"}]`)
+		case http.MethodPatch:
+			var body struct {
+				Items []*gofastly.BatchDictionaryItem `json:"items"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("unexpected body: %s", err)
+			}
+			batches = append(batches, len(body.Items))
+			fmt.Fprint(w, `{"status": "ok"}`)
+		default:
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := make(map[string]string)
+	for i := 0; i < gofastly.BatchModifyMaximumOperations+10; i++ {
+		local[fmt.Sprintf("key-%d", i)] = "value"
+	}
+
+	summary, err := syncDictionaryItems(conn, "123", "456", local, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := (dictionaryItemsSummary{added: len(local), removed: 1}); summary != expected {
+		t.Errorf("expected summary %s, got %s", expected, summary)
+	}
+	if expected := []int{gofastly.BatchModifyMaximumOperations, 11}; !reflect.DeepEqual(batches, expected) {
+		t.Errorf("expected batches of %v operations, got %v", expected, batches)
+	}
+}
+
+func TestDeleteDictionaryItems(t *testing.T) {
+	var deleted []*gofastly.BatchDictionaryItem
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `[{"item_key": "declared", "item_value": "value"}, {"item_key": "external", "item_value": "value"}]`)
+		case http.MethodPatch:
+			var body struct {
+				Items []*gofastly.BatchDictionaryItem `json:"items"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("unexpected body: %s", err)
+			}
+			deleted = append(deleted, body.Items...)
+			fmt.Fprint(w, `{"status": "ok"}`)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := deleteDictionaryItems(conn, "123", "456", []string{"declared", "missing"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []*gofastly.BatchDictionaryItem{
+		{Operation: gofastly.DeleteBatchOperation, ItemKey: "declared"},
+	}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, deleted)
+	}
+}
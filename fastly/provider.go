@@ -2,11 +2,14 @@ package fastly
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
 	"github.com/fastly/terraform-provider-fastly/version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const TerraformProviderProductUserAgent = "terraform-provider-fastly"
@@ -33,9 +36,79 @@ func Provider() *schema.Provider {
 				Default:     false,
 				Description: "Set this to `true` if you only need data source that does not require authentication such as `fastly_ip_ranges`",
 			},
+			"version_check_delay": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FASTLY_VERSION_CHECK_DELAY", 1),
+				Description:      "How long to wait, in seconds, before checking that a cloned service version is available. Default `1`",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"version_check_interval": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FASTLY_VERSION_CHECK_INTERVAL", 1),
+				Description:      "The minimum time to wait, in seconds, between checks that a cloned service version is available. Default `1`",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"parallelism": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FASTLY_PARALLELISM", DefaultParallelism),
+				Description:      fmt.Sprintf("The maximum number of blocks of a service, such as logging endpoints, which are created, updated or read concurrently. Concurrent modifications of a service version aren't guaranteed to be safe by the Fastly API client, so this is opt-in. Default `%d`, which processes them one at a time", DefaultParallelism),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+			"max_retries": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FASTLY_MAX_RETRIES", DefaultMaxRetries),
+				Description:      fmt.Sprintf("The maximum number of times a request which was rate limited or failed with a transient error is retried. Set to `0` to disable retries. Default `%d`", DefaultMaxRetries),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"retry_min_wait": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FASTLY_RETRY_MIN_WAIT", int(DefaultRetryMinWait/time.Second)),
+				Description:      fmt.Sprintf("The time to wait, in seconds, before the first retry of a request. The wait doubles with every further retry. Default `%d`", int(DefaultRetryMinWait/time.Second)),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"retry_max_wait": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FASTLY_RETRY_MAX_WAIT", int(DefaultRetryMaxWait/time.Second)),
+				Description:      fmt.Sprintf("The maximum time to wait, in seconds, before retrying a request, including when the API asks to wait longer. Default `%d`", int(DefaultRetryMaxWait/time.Second)),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"write_rate_limit": {
+				Type:             schema.TypeFloat,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FASTLY_WRITE_RATE_LIMIT", 0.0),
+				Description:      "The maximum number of requests per second modifying anything through the Fastly API, shared by all resources. Set to `0` for no limit. Default `0`",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.FloatAtLeast(0)),
+			},
+			"write_burst": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FASTLY_WRITE_BURST", DefaultWriteBurst),
+				Description:      fmt.Sprintf("The number of requests modifying anything which can be made at once before `write_rate_limit` applies. Default `%d`", DefaultWriteBurst),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+			"vcl_syntax_check": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FASTLY_VCL_SYNTAX_CHECK", false),
+				Description: "Set this to `true` to check the syntax of custom VCL and VCL snippets when planning, instead of only when the service version is validated by Fastly. Default `false`",
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
+			"fastly_datacenters":                  dataSourceFastlyDatacenters(),
+			"fastly_edge_check":                   dataSourceFastlyEdgeCheck(),
+			"fastly_events":                       dataSourceFastlyEvents(),
 			"fastly_ip_ranges":                    dataSourceFastlyIPRanges(),
+			"fastly_service":                      dataSourceFastlyService(),
+			"fastly_service_generated_vcl":        dataSourceFastlyServiceGeneratedVCL(),
+			"fastly_service_stats":                dataSourceFastlyServiceStats(),
+			"fastly_service_version_diff":         dataSourceFastlyServiceVersionDiff(),
+			"fastly_services":                     dataSourceFastlyServices(),
 			"fastly_tls_activation":               dataSourceFastlyTLSActivation(),
 			"fastly_tls_activation_ids":           dataSourceFastlyTLSActivationIds(),
 			"fastly_tls_certificate":              dataSourceFastlyTLSCertificate(),
@@ -49,6 +122,8 @@ func Provider() *schema.Provider {
 			"fastly_tls_private_key_ids":          dataSourceFastlyTLSPrivateKeyIDs(),
 			"fastly_tls_subscription":             dataSourceFastlyTLSSubscription(),
 			"fastly_tls_subscription_ids":         dataSourceFastlyTLSSubscriptionIDs(),
+			"fastly_tokens":                       dataSourceFastlyTokens(),
+			"fastly_usage":                        dataSourceFastlyUsage(),
 			"fastly_waf_rules":                    dataSourceFastlyWAFRules(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
@@ -57,13 +132,17 @@ func Provider() *schema.Provider {
 			"fastly_service_acl_entries_v1":             resourceServiceAclEntriesV1(),
 			"fastly_service_dictionary_items_v1":        resourceServiceDictionaryItemsV1(),
 			"fastly_service_dynamic_snippet_content_v1": resourceServiceDynamicSnippetContentV1(),
+			"fastly_service_activation":                 resourceServiceActivation(),
+			"fastly_service_purge":                      resourceServicePurge(),
 			"fastly_service_waf_configuration":          resourceServiceWAFConfigurationV1(),
 			"fastly_tls_activation":                     resourceFastlyTLSActivation(),
 			"fastly_tls_certificate":                    resourceFastlyTLSCertificate(),
+			"fastly_tls_configuration":                  resourceFastlyTLSConfiguration(),
 			"fastly_tls_private_key":                    resourceFastlyTLSPrivateKey(),
 			"fastly_tls_platform_certificate":           resourceFastlyTLSPlatformCertificate(),
 			"fastly_tls_subscription":                   resourceFastlyTLSSubscription(),
 			"fastly_tls_subscription_validation":        resourceFastlyTLSSubscriptionValidation(),
+			"fastly_token":                              resourceFastlyToken(),
 			"fastly_user_v1":                            resourceUserV1(),
 		},
 	}
@@ -74,6 +153,19 @@ func Provider() *schema.Provider {
 			BaseURL:   d.Get("base_url").(string),
 			NoAuth:    d.Get("no_auth").(bool),
 			UserAgent: provider.UserAgent(TerraformProviderProductUserAgent, version.ProviderVersion),
+
+			VersionCheckDelay:    time.Duration(d.Get("version_check_delay").(int)) * time.Second,
+			VersionCheckInterval: time.Duration(d.Get("version_check_interval").(int)) * time.Second,
+			Parallelism:          d.Get("parallelism").(int),
+
+			MaxRetries:   d.Get("max_retries").(int),
+			RetryMinWait: time.Duration(d.Get("retry_min_wait").(int)) * time.Second,
+			RetryMaxWait: time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+
+			WriteRateLimit: d.Get("write_rate_limit").(float64),
+			WriteBurst:     d.Get("write_burst").(int),
+
+			VCLSyntaxCheck: d.Get("vcl_syntax_check").(bool),
 		}
 		return config.Client()
 	}
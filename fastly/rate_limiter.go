@@ -0,0 +1,136 @@
+package fastly
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+const (
+	DefaultWriteBurst = 10
+
+	// RateLimitWarningThreshold is the remaining number of requests modifying
+	// services, as reported by the API, below which a warning is returned.
+	RateLimitWarningThreshold = 100
+)
+
+// RateLimitTransport limits the rate of requests modifying anything through
+// the Fastly API with a token bucket, which holds up to Burst tokens and is
+// refilled with Rate tokens per second. A Rate of 0 disables the limit.
+//
+// It is shared by every resource using the provider, so that the budget of
+// requests of the API is spent evenly. It also records the remaining budget
+// reported by the API.
+type RateLimitTransport struct {
+	Next  http.RoundTripper
+	Rate  float64
+	Burst int
+
+	lock      sync.Mutex
+	tokens    float64
+	last      time.Time
+	remaining int
+	reported  bool
+	warned    bool
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Rate > 0 && !isReadOnly(req.Method) {
+		if wait := t.reserve(time.Now()); wait > 0 {
+			log.Printf("[INFO] Throttling %s %s for %s to stay within %g writes per second", req.Method, req.URL.Path, wait.Round(time.Millisecond), t.Rate)
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-req.Context().Done():
+				timer.Stop()
+				t.cancel()
+				return nil, req.Context().Err()
+			case <-timer.C:
+			}
+		}
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	if err == nil {
+		if remaining, err := strconv.Atoi(resp.Header.Get(RateLimitRemainingHeader)); err == nil {
+			t.setRemaining(remaining)
+		}
+	}
+	return resp, err
+}
+
+// reserve takes a token from the bucket and returns how long to wait until
+// the token is available. Tokens are taken in advance so that waiting requests
+// are let through in turn.
+func (t *RateLimitTransport) reserve(now time.Time) time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	burst := float64(t.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	if t.last.IsZero() {
+		t.tokens = burst
+	} else if elapsed := now.Sub(t.last).Seconds(); elapsed > 0 {
+		t.tokens += elapsed * t.Rate
+		if t.tokens > burst {
+			t.tokens = burst
+		}
+	}
+	t.last = now
+
+	t.tokens--
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / t.Rate * float64(time.Second))
+}
+
+// cancel returns the token of a request which was cancelled while waiting.
+func (t *RateLimitTransport) cancel() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.tokens++
+}
+
+func (t *RateLimitTransport) setRemaining(remaining int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.remaining = remaining
+	t.reported = true
+}
+
+// lowBudgetWarning returns a warning the first time the remaining budget of
+// requests reported by the API is below RateLimitWarningThreshold.
+func (t *RateLimitTransport) lowBudgetWarning() diag.Diagnostics {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.reported || t.warned || t.remaining >= RateLimitWarningThreshold {
+		return nil
+	}
+	t.warned = true
+
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Fastly API rate limit almost exhausted",
+			Detail:   fmt.Sprintf("The Fastly API reports that only %d requests modifying services remain in the current rate limiting window. Further changes may fail until it resets. Consider lowering the write_rate_limit of the provider.", t.remaining),
+		},
+	}
+}
+
+func isReadOnly(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
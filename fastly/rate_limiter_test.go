@@ -0,0 +1,108 @@
+package fastly
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestRateLimitTransport_reserve(t *testing.T) {
+	transport := &RateLimitTransport{
+		Rate:  2,
+		Burst: 2,
+	}
+	now := time.Now()
+
+	cases := []struct {
+		name     string
+		now      time.Time
+		expected time.Duration
+	}{
+		{"first token of the burst", now, 0},
+		{"second token of the burst", now, 0},
+		{"bucket empty", now, 500 * time.Millisecond},
+		{"queued behind the previous request", now, time.Second},
+		{"refilled", now.Add(5 * time.Second), 0},
+	}
+
+	for _, c := range cases {
+		if wait := transport.reserve(c.now); wait != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, wait)
+		}
+	}
+}
+
+func TestRateLimitTransport_throttlesWrites(t *testing.T) {
+	var remaining = 150
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			remaining -= 30
+			w.Header().Set(RateLimitRemainingHeader, strconv.Itoa(remaining))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"service_id": "service", "version": 1, "name": "condition"}`))
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &RateLimitTransport{
+		Next:  conn.HTTPClient.Transport,
+		Rate:  20,
+		Burst: 1,
+	}
+	conn.HTTPClient.Transport = transport
+	client := &FastlyClient{conn: conn, rateLimit: transport}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := conn.GetCondition(&gofastly.GetConditionInput{
+			ServiceID:      "service",
+			ServiceVersion: 1,
+			Name:           "condition",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Errorf("Expected reads not to be throttled, took %s", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := conn.CreateCondition(&gofastly.CreateConditionInput{
+			ServiceID:      "service",
+			ServiceVersion: 1,
+			Name:           "condition",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		diags := client.rateLimitWarning()
+		switch i {
+		case 1:
+			// 90 requests remain, which is below the threshold.
+			if len(diags) != 1 || diags[0].Severity != diag.Warning {
+				t.Errorf("Expected a warning with %d requests remaining, got %#v", remaining, diags)
+			}
+		default:
+			// There is no warning above the threshold, and only one warning.
+			if len(diags) != 0 {
+				t.Errorf("Expected no warning with %d requests remaining, got %#v", remaining, diags)
+			}
+		}
+	}
+	// The first write is let through by the burst, the others are 50ms apart.
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected writes to be throttled, took %s", elapsed)
+	}
+}
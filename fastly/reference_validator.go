@@ -0,0 +1,238 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// unknownValue is the placeholder of the SDK for values which are not known
+// until apply.
+const unknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// serviceReference describes an attribute of a block which references another
+// block by name.
+type serviceReference struct {
+	// block is the key of the referenced block.
+	block string
+	// conditionType is the type the referenced condition must have.
+	conditionType string
+}
+
+// serviceReferences are the attributes, found in any block, which reference
+// another block by name.
+var serviceReferences = map[string]serviceReference{
+	"request_condition":  {block: "condition", conditionType: "REQUEST"},
+	"cache_condition":    {block: "condition", conditionType: "CACHE"},
+	"response_condition": {block: "condition", conditionType: "RESPONSE"},
+	"prefetch_condition": {block: "condition", conditionType: "PREFETCH"},
+	"healthcheck":        {block: "healthcheck"},
+	"backends":           {block: "backend"},
+	"response_object":    {block: "response_object"},
+}
+
+// referenceError is a reference which cannot be resolved, at the path of the
+// referencing attribute.
+type referenceError struct {
+	path    cty.Path
+	message string
+}
+
+func (e referenceError) String() string {
+	return fmt.Sprintf("%s: %s", formatAttributePath(e.path), e.message)
+}
+
+// validateReferences returns a CustomizeDiffFunc which checks that every named
+// reference between the blocks of the resource, such as the request_condition
+// of a header, resolves to a block of the right kind. Otherwise a dangling
+// reference is only reported by the API when the cloned version is validated.
+//
+// The blocks are found in the schema of the resource, so it must be called
+// once all the attribute handlers have been registered.
+func validateReferences(s *schema.Resource) schema.CustomizeDiffFunc {
+	blocks := referencingBlocks(s.Schema)
+
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		var changed bool
+		for key, attrs := range blocks {
+			if d.HasChange(key) {
+				changed = true
+			}
+			for _, attr := range attrs {
+				if d.HasChange(serviceReferences[attr].block) {
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		var errs []string
+		for _, e := range resolveReferences(d, blocks) {
+			errs = append(errs, e.String())
+		}
+
+		if len(errs) > 0 {
+			sort.Strings(errs)
+			return fmt.Errorf("%s", strings.Join(errs, "\n"))
+		}
+		return nil
+	}
+}
+
+// referencingBlocks returns the blocks of the schema with attributes which
+// reference another block of the schema, along with those attributes.
+func referencingBlocks(s map[string]*schema.Schema) map[string][]string {
+	blocks := make(map[string][]string)
+	for key, sch := range s {
+		elem, ok := sch.Elem.(*schema.Resource)
+		if !ok {
+			continue
+		}
+		for attr := range elem.Schema {
+			if ref, ok := serviceReferences[attr]; ok {
+				if _, ok := s[ref.block]; ok {
+					blocks[key] = append(blocks[key], attr)
+				}
+			}
+		}
+		sort.Strings(blocks[key])
+	}
+	return blocks
+}
+
+// resolveReferences returns the references of the blocks which cannot be
+// resolved. Blocks which are not known until apply are skipped.
+func resolveReferences(d *schema.ResourceDiff, blocks map[string][]string) []referenceError {
+	// The defined blocks are looked up by name.
+	defined := make(map[string]map[string]map[string]interface{})
+	for _, attrs := range blocks {
+		for _, attr := range attrs {
+			key := serviceReferences[attr].block
+			if _, ok := defined[key]; ok || !d.NewValueKnown(key) {
+				continue
+			}
+			defined[key] = make(map[string]map[string]interface{})
+			for _, e := range blockElements(d.Get(key)) {
+				if name, ok := e["name"].(string); ok {
+					defined[key][name] = e
+				}
+			}
+		}
+	}
+
+	var errs []referenceError
+	for key, attrs := range blocks {
+		if !d.NewValueKnown(key) {
+			continue
+		}
+		_, isList := d.Get(key).([]interface{})
+		for i, e := range blockElements(d.Get(key)) {
+			path := cty.Path{cty.GetAttrStep{Name: key}}
+			if isList {
+				path = path.Index(cty.NumberIntVal(int64(i)))
+			} else if name, ok := e["name"].(string); ok {
+				path = path.Index(cty.StringVal(name))
+			}
+
+			for _, attr := range attrs {
+				ref := serviceReferences[attr]
+				names, ok := defined[ref.block]
+				if !ok {
+					continue
+				}
+				for _, name := range referencedNames(e[attr]) {
+					if msg := checkReference(ref, name, names); msg != "" {
+						errs = append(errs, referenceError{
+							path:    path.GetAttr(attr),
+							message: msg,
+						})
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// checkReference returns an error message if the named block is not defined,
+// or if it is a condition of the wrong type.
+func checkReference(ref serviceReference, name string, defined map[string]map[string]interface{}) string {
+	e, ok := defined[name]
+	if !ok {
+		return fmt.Sprintf("%s %q is not defined", ref.block, name)
+	}
+	if ref.conditionType != "" {
+		if t, _ := e["type"].(string); t != unknownValue && !strings.EqualFold(t, ref.conditionType) {
+			return fmt.Sprintf("%s %q is of type %s, expected %s", ref.block, name, t, ref.conditionType)
+		}
+	}
+	return ""
+}
+
+func blockElements(v interface{}) []map[string]interface{} {
+	var elems []interface{}
+	switch v := v.(type) {
+	case *schema.Set:
+		elems = v.List()
+	case []interface{}:
+		elems = v
+	}
+
+	var result []map[string]interface{}
+	for _, e := range elems {
+		if m, ok := e.(map[string]interface{}); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// referencedNames returns the names referenced by the value of an attribute,
+// which is either a single name or a set of names. Empty and unknown names
+// are skipped.
+func referencedNames(v interface{}) []string {
+	var values []interface{}
+	switch v := v.(type) {
+	case string:
+		values = []interface{}{v}
+	case *schema.Set:
+		values = v.List()
+	}
+
+	var names []string
+	for _, v := range values {
+		if name, ok := v.(string); ok && name != "" && name != unknownValue {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatAttributePath formats a path as it would be written in configuration,
+// with the elements of sets indexed by name.
+func formatAttributePath(path cty.Path) string {
+	var b strings.Builder
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(step.Name)
+		case cty.IndexStep:
+			if step.Key.Type() == cty.String {
+				fmt.Fprintf(&b, "[%q]", step.Key.AsString())
+			} else {
+				fmt.Fprintf(&b, "[%s]", step.Key.AsBigFloat().String())
+			}
+		}
+	}
+	return b.String()
+}
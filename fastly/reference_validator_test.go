@@ -0,0 +1,181 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestReferencingBlocks(t *testing.T) {
+	blocks := referencingBlocks(resourceServiceV1().Schema)
+
+	expected := map[string][]string{
+		"backend":         {"healthcheck", "request_condition"},
+		"cache_setting":   {"cache_condition"},
+		"director":        {"backends"},
+		"header":          {"cache_condition", "request_condition", "response_condition"},
+		"pool":            {"healthcheck", "request_condition"},
+		"response_object": {"cache_condition", "request_condition"},
+		"waf":             {"prefetch_condition", "response_object"},
+	}
+	for key, attrs := range expected {
+		if !reflect.DeepEqual(blocks[key], attrs) {
+			t.Errorf("%s: expected %#v, got %#v", key, attrs, blocks[key])
+		}
+	}
+	if !reflect.DeepEqual(blocks["logging_datadog"], []string{"response_condition"}) {
+		t.Errorf("logging_datadog: expected the response_condition, got %#v", blocks["logging_datadog"])
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	config := func(blocks map[string]interface{}) *terraform.ResourceConfig {
+		raw := map[string]interface{}{
+			"name": "test",
+			"domain": []interface{}{
+				map[string]interface{}{"name": "example.com"},
+			},
+			"condition": []interface{}{
+				map[string]interface{}{"name": "req", "statement": "req.url", "type": "REQUEST"},
+				map[string]interface{}{"name": "prefetch", "statement": "req.url", "type": "PREFETCH"},
+			},
+			"healthcheck": []interface{}{
+				map[string]interface{}{"name": "hc", "host": "example.com", "path": "/"},
+			},
+			"backend": []interface{}{
+				map[string]interface{}{"name": "origin", "address": "example.com", "healthcheck": "hc", "request_condition": "req"},
+			},
+		}
+		for k, v := range blocks {
+			raw[k] = v
+		}
+		return terraform.NewResourceConfigRaw(raw)
+	}
+
+	cases := map[string]struct {
+		blocks   map[string]interface{}
+		expected []string
+	}{
+		"valid references": {
+			blocks: map[string]interface{}{
+				"director": []interface{}{
+					map[string]interface{}{"name": "dir", "backends": []interface{}{"origin"}},
+				},
+				"response_object": []interface{}{
+					map[string]interface{}{"name": "blocked", "request_condition": "req"},
+				},
+				"waf": []interface{}{
+					map[string]interface{}{"response_object": "blocked", "prefetch_condition": "prefetch"},
+				},
+			},
+		},
+		"dangling references": {
+			blocks: map[string]interface{}{
+				"director": []interface{}{
+					map[string]interface{}{"name": "dir", "backends": []interface{}{"origin", "missing"}},
+				},
+				"header": []interface{}{
+					map[string]interface{}{"name": "h", "action": "set", "type": "request", "destination": "http.X", "request_condition": "nope"},
+				},
+			},
+			expected: []string{
+				`director["dir"].backends: backend "missing" is not defined`,
+				`header["h"].request_condition: condition "nope" is not defined`,
+			},
+		},
+		"wrong condition types": {
+			blocks: map[string]interface{}{
+				"response_object": []interface{}{
+					map[string]interface{}{"name": "blocked", "cache_condition": "req"},
+				},
+				"waf": []interface{}{
+					map[string]interface{}{"response_object": "blocked", "prefetch_condition": "req"},
+				},
+			},
+			expected: []string{
+				`response_object["blocked"].cache_condition: condition "req" is of type REQUEST, expected CACHE`,
+				`waf[0].prefetch_condition: condition "req" is of type REQUEST, expected PREFETCH`,
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := resourceServiceV1().Diff(context.Background(), nil, config(c.blocks), nil)
+			if len(c.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			for _, msg := range c.expected {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("expected %q in %q", msg, err)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatAttributePath(t *testing.T) {
+	cases := []struct {
+		path     cty.Path
+		expected string
+	}{
+		{cty.GetAttrPath("waf").IndexInt(0).GetAttr("prefetch_condition"), "waf[0].prefetch_condition"},
+		{cty.GetAttrPath("header").IndexString("X-Foo").GetAttr("request_condition"), `header["X-Foo"].request_condition`},
+	}
+
+	for _, c := range cases {
+		if s := formatAttributePath(c.path); s != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, s)
+		}
+	}
+}
+
+func TestAccFastlyServiceV1_danglingReference(t *testing.T) {
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccServiceV1Config_danglingReference(name, domain),
+				ExpectError: regexp.MustCompile(`backend\["amazon docs"\]\.request_condition: condition "missing" is not defined`),
+			},
+		},
+	})
+}
+
+func testAccServiceV1Config_danglingReference(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address           = "aws.amazon.com"
+    name              = "amazon docs"
+    request_condition = "missing"
+  }
+
+  force_destroy = true
+}`, name, domain)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"reflect"
 	"strings"
 
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
@@ -19,6 +20,7 @@ func resourceServiceAclEntriesV1() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceServiceACLEntriesV1Import,
 		},
+		CustomizeDiff: resourceServiceAclEntriesV1CustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"service_id": {
@@ -34,11 +36,13 @@ func resourceServiceAclEntriesV1() *schema.Resource {
 				ForceNew:    true,
 				Description: "The ID of the ACL that the items belong to",
 			},
+			"manage_mode": manageModeSchema("entries"),
 			"entry": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Description: "ACL Entries",
-				MaxItems:    gofastly.MaximumACLSize,
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Description:   "ACL Entries",
+				MaxItems:      gofastly.MaximumACLSize,
+				ConflictsWith: []string{"cidrs", "cidrs_file"},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"id": {
@@ -70,6 +74,30 @@ func resourceServiceAclEntriesV1() *schema.Resource {
 					},
 				},
 			},
+			"cidrs": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Description:   "CIDRs or IP addresses matched by the ACL, instead of `entry`. They are merged with those of `cidrs_file`, and the overlapping and adjacent ranges are merged into as few entries as possible. Negated entries are left alone",
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"entry"},
+			},
+			"cidrs_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "The path of a file of CIDRs or IP addresses matched by the ACL, one per line, instead of `entry`. Blank lines and comments starting with `#` are ignored",
+				ConflictsWith: []string{"entry"},
+			},
+			"normalized_cidrs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The CIDRs of the entries made from `cidrs` and `cidrs_file`, once the overlapping and adjacent ranges are merged",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"cidrs_entry_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: fmt.Sprintf("The number of entries made from `cidrs` and `cidrs_file`, which can't exceed the limit of %d entries of an ACL", gofastly.MaximumACLSize),
+			},
 		},
 	}
 }
@@ -79,6 +107,20 @@ func resourceServiceAclEntriesV1Create(ctx context.Context, d *schema.ResourceDa
 
 	serviceID := d.Get("service_id").(string)
 	aclID := d.Get("acl_id").(string)
+
+	cidrs, ok, err := aclCIDRsConfig(d.Get("cidrs").(*schema.Set), d.Get("cidrs_file").(string))
+	if err != nil {
+		return diag.Errorf("Error creating ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
+	}
+	if ok {
+		if err := syncACLCIDRs(conn, serviceID, aclID, cidrs, nil, d.Get("manage_mode").(string) == ManageModeAdditive); err != nil {
+			return diag.Errorf("Error creating ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
+		}
+
+		d.SetId(fmt.Sprintf("%s/%s", serviceID, aclID))
+		return append(resourceServiceAclEntriesV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
+	}
+
 	entries := d.Get("entry").(*schema.Set)
 
 	var batchACLEntries = []*gofastly.BatchACLEntry{}
@@ -95,14 +137,20 @@ func resourceServiceAclEntriesV1Create(ctx context.Context, d *schema.ResourceDa
 		})
 	}
 
+	if d.Get("manage_mode").(string) == ManageModeAdditive {
+		if err := adoptACLEntries(conn, serviceID, aclID, batchACLEntries); err != nil {
+			return diag.Errorf("Error creating ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
+		}
+	}
+
 	// Process the batch operations
-	err := executeBatchACLOperations(conn, serviceID, aclID, batchACLEntries)
+	err = executeBatchACLOperations(conn, serviceID, aclID, batchACLEntries)
 	if err != nil {
 		return diag.Errorf("Error creating ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", serviceID, aclID))
-	return resourceServiceAclEntriesV1Read(ctx, d, meta)
+	return append(resourceServiceAclEntriesV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
 }
 
 func resourceServiceAclEntriesV1Read(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -120,6 +168,27 @@ func resourceServiceAclEntriesV1Read(_ context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
+	if d.Get("cidrs").(*schema.Set).Len() > 0 || d.Get("cidrs_file").(string) != "" {
+		return diag.FromErr(readACLCIDRs(d, aclEntries))
+	}
+
+	// Only the declared entries are read when additive.
+	if d.Get("manage_mode").(string) == ManageModeAdditive {
+		declared := make(map[string]bool)
+		for _, vRaw := range d.Get("entry").(*schema.Set).List() {
+			val := vRaw.(map[string]interface{})
+			declared[aclEntryKey(val["ip"].(string), val["subnet"].(string))] = true
+		}
+
+		var owned []*gofastly.ACLEntry
+		for _, e := range aclEntries {
+			if declared[aclEntryKey(e.IP, e.Subnet)] {
+				owned = append(owned, e)
+			}
+		}
+		aclEntries = owned
+	}
+
 	err = d.Set("entry", flattenAclEntries(aclEntries))
 	if err != nil {
 		return diag.FromErr(err)
@@ -134,6 +203,33 @@ func resourceServiceAclEntriesV1Update(ctx context.Context, d *schema.ResourceDa
 
 	serviceID := d.Get("service_id").(string)
 	aclID := d.Get("acl_id").(string)
+	additive := d.Get("manage_mode").(string) == ManageModeAdditive
+
+	cidrs, ok, err := aclCIDRsConfig(d.Get("cidrs").(*schema.Set), d.Get("cidrs_file").(string))
+	if err != nil {
+		return diag.Errorf("Error updating ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
+	}
+
+	// The entries made from CIDRs are synced with the remote entries, deleting
+	// those of the previous CIDRs when additive, unless they were read while
+	// exclusive.
+	if ok || d.HasChange("normalized_cidrs") {
+		var owned []string
+		if !switchedToAdditive(d) {
+			o, _ := d.GetChange("normalized_cidrs")
+			for _, c := range o.([]interface{}) {
+				owned = append(owned, c.(string))
+			}
+		}
+
+		if err := syncACLCIDRs(conn, serviceID, aclID, cidrs, owned, additive || !ok); err != nil {
+			return diag.Errorf("Error updating ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
+		}
+
+		if ok {
+			return append(resourceServiceAclEntriesV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
+		}
+	}
 
 	var batchACLEntries = []*gofastly.BatchACLEntry{}
 
@@ -163,8 +259,12 @@ func resourceServiceAclEntriesV1Update(ctx context.Context, d *schema.ResourceDa
 			return diag.FromErr(err)
 		}
 
-		// DELETE removed resources
+		// DELETE removed resources, unless the entries of the state were read
+		// while exclusive
 		for _, resource := range diffResult.Deleted {
+			if switchedToAdditive(d) {
+				break
+			}
 			resource := resource.(map[string]interface{})
 
 			batchACLEntries = append(batchACLEntries, &gofastly.BatchACLEntry{
@@ -202,13 +302,19 @@ func resourceServiceAclEntriesV1Update(ctx context.Context, d *schema.ResourceDa
 		}
 	}
 
+	if additive {
+		if err := adoptACLEntries(conn, serviceID, aclID, batchACLEntries); err != nil {
+			return diag.Errorf("Error updating ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
+		}
+	}
+
 	// Process the batch operations
-	err := executeBatchACLOperations(conn, serviceID, aclID, batchACLEntries)
+	err = executeBatchACLOperations(conn, serviceID, aclID, batchACLEntries)
 	if err != nil {
 		return diag.Errorf("Error updating ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
 	}
 
-	return resourceServiceAclEntriesV1Read(ctx, d, meta)
+	return append(resourceServiceAclEntriesV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
 }
 
 func resourceServiceAclEntriesV1Delete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -216,6 +322,25 @@ func resourceServiceAclEntriesV1Delete(_ context.Context, d *schema.ResourceData
 
 	serviceID := d.Get("service_id").(string)
 	aclID := d.Get("acl_id").(string)
+
+	// The state doesn't hold the entries made from CIDRs, so the remote
+	// entries of the CIDRs are deleted instead, or every remote entry which
+	// isn't negated when exclusive.
+	if cidrs := d.Get("normalized_cidrs").([]interface{}); len(cidrs) > 0 {
+		var owned []string
+		for _, c := range cidrs {
+			owned = append(owned, c.(string))
+		}
+
+		additive := d.Get("manage_mode").(string) == ManageModeAdditive
+		if err := syncACLCIDRs(conn, serviceID, aclID, nil, owned, additive); err != nil {
+			return diag.Errorf("Error deleting ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
+		}
+
+		d.SetId("")
+		return nil
+	}
+
 	entries := d.Get("entry").(*schema.Set)
 
 	var batchACLEntries = []*gofastly.BatchACLEntry{}
@@ -284,9 +409,156 @@ func resourceServiceACLEntriesV1Import(_ context.Context, d *schema.ResourceData
 		return nil, fmt.Errorf("Error importing ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
 	}
 
+	err = d.Set("manage_mode", ManageModeExclusive)
+	if err != nil {
+		return nil, fmt.Errorf("Error importing ACL entries: service %s, ACL %s, %s", serviceID, aclID, err)
+	}
+
 	return []*schema.ResourceData{d}, nil
 }
 
+// resourceServiceAclEntriesV1CustomizeDiff plans the entries made from cidrs
+// and cidrs_file, failing when they exceed the limit of entries of an ACL.
+func resourceServiceAclEntriesV1CustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("cidrs") || !d.NewValueKnown("cidrs_file") {
+		if err := d.SetNewComputed("normalized_cidrs"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("cidrs_entry_count")
+	}
+
+	cidrs, ok, err := aclCIDRsConfig(d.Get("cidrs").(*schema.Set), d.Get("cidrs_file").(string))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		cidrs = []string{}
+	}
+
+	var current []string
+	for _, c := range d.Get("normalized_cidrs").([]interface{}) {
+		current = append(current, c.(string))
+	}
+	if (len(current) == 0 && len(cidrs) == 0) || reflect.DeepEqual(current, cidrs) {
+		return nil
+	}
+
+	if err := d.SetNew("normalized_cidrs", cidrs); err != nil {
+		return err
+	}
+	return d.SetNew("cidrs_entry_count", len(cidrs))
+}
+
+// aclCIDRsConfig returns the normalized CIDRs of cidrs and cidrs_file, and
+// false when neither is set.
+func aclCIDRsConfig(cidrs *schema.Set, path string) ([]string, bool, error) {
+	if cidrs.Len() == 0 && path == "" {
+		return nil, false, nil
+	}
+
+	var all []string
+	for _, c := range cidrs.List() {
+		all = append(all, c.(string))
+	}
+	if path != "" {
+		fromFile, err := readCIDRsFile(path)
+		if err != nil {
+			return nil, true, err
+		}
+		all = append(all, fromFile...)
+	}
+
+	normalized, err := normalizeCIDRs(all)
+	if err != nil {
+		return nil, true, err
+	}
+	if max := gofastly.MaximumACLSize; len(normalized) > max {
+		return nil, true, fmt.Errorf("the CIDRs make %d ACL entries once merged, more than the limit of %d", len(normalized), max)
+	}
+	return normalized, true, nil
+}
+
+// syncACLCIDRs turns the remote entries of an ACL into the given CIDRs.
+func syncACLCIDRs(conn *gofastly.Client, serviceID, aclID string, cidrs, owned []string, additive bool) error {
+	remote, err := conn.ListACLEntries(&gofastly.ListACLEntriesInput{
+		ServiceID: serviceID,
+		ACLID:     aclID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return executeBatchACLOperations(conn, serviceID, aclID, diffACLCIDRs(remote, cidrs, owned, additive))
+}
+
+// readACLCIDRs sets the CIDRs of the remote entries, only those made from the
+// CIDRs of the state when additive.
+func readACLCIDRs(d *schema.ResourceData, aclEntries []*gofastly.ACLEntry) error {
+	var owned map[string]bool
+	if d.Get("manage_mode").(string) == ManageModeAdditive {
+		owned = make(map[string]bool)
+		for _, c := range d.Get("normalized_cidrs").([]interface{}) {
+			owned[c.(string)] = true
+		}
+	}
+
+	var cidrs []string
+	for _, e := range aclEntries {
+		if c, ok := aclEntryCIDR(e); ok && (owned == nil || owned[c]) {
+			cidrs = append(cidrs, c)
+		}
+	}
+
+	normalized, err := normalizeCIDRs(cidrs)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("normalized_cidrs", normalized); err != nil {
+		return err
+	}
+	return d.Set("cidrs_entry_count", len(normalized))
+}
+
+// aclEntryKey identifies an ACL entry by its IP and subnet.
+func aclEntryKey(ip, subnet string) string {
+	return ip + "/" + subnet
+}
+
+// adoptACLEntries turns the creation of entries whose IP and subnet already
+// exist remotely into updates of the remote entries, so that the additive
+// manage_mode takes over the entries added outside of Terraform rather than
+// duplicating them.
+func adoptACLEntries(conn *gofastly.Client, serviceID, aclID string, batchACLEntries []*gofastly.BatchACLEntry) error {
+	remote, err := conn.ListACLEntries(&gofastly.ListACLEntriesInput{
+		ServiceID: serviceID,
+		ACLID:     aclID,
+	})
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]string, len(remote))
+	for _, e := range remote {
+		ids[aclEntryKey(e.IP, e.Subnet)] = e.ID
+	}
+
+	for _, e := range batchACLEntries {
+		if e.Operation != gofastly.CreateBatchOperation || e.IP == nil {
+			continue
+		}
+		var subnet string
+		if e.Subnet != nil {
+			subnet = *e.Subnet
+		}
+		if id, ok := ids[aclEntryKey(*e.IP, subnet)]; ok {
+			e.Operation = gofastly.UpdateBatchOperation
+			e.ID = gofastly.String(id)
+		}
+	}
+
+	return nil
+}
+
 func executeBatchACLOperations(conn *gofastly.Client, serviceID, aclID string, batchACLEntries []*gofastly.BatchACLEntry) error {
 
 	batchSize := gofastly.BatchModifyMaximumOperations
@@ -1,15 +1,21 @@
 package fastly
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -62,6 +68,134 @@ func TestResourceFastlyFlattenAclEntries(t *testing.T) {
 	}
 }
 
+func TestAdoptACLEntries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/123/acl/456/entries" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"id": "existing", "ip": "127.0.0.1", "subnet": "24"}]`)
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []*gofastly.BatchACLEntry{
+		{Operation: gofastly.CreateBatchOperation, IP: gofastly.String("127.0.0.1"), Subnet: gofastly.String("24")},
+		{Operation: gofastly.CreateBatchOperation, IP: gofastly.String("127.0.0.1"), Subnet: gofastly.String("32")},
+		{Operation: gofastly.DeleteBatchOperation, ID: gofastly.String("other")},
+	}
+	if err := adoptACLEntries(conn, "123", "456", entries); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []*gofastly.BatchACLEntry{
+		{Operation: gofastly.UpdateBatchOperation, ID: gofastly.String("existing"), IP: gofastly.String("127.0.0.1"), Subnet: gofastly.String("24")},
+		{Operation: gofastly.CreateBatchOperation, IP: gofastly.String("127.0.0.1"), Subnet: gofastly.String("32")},
+		{Operation: gofastly.DeleteBatchOperation, ID: gofastly.String("other")},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\ngot: %#v", expected, entries)
+	}
+}
+
+func TestResourceServiceAclEntriesV1Update_switchToAdditive(t *testing.T) {
+	var ops []*gofastly.BatchACLEntry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `[{"id": "declared", "ip": "192.0.2.1", "subnet": "32"}, {"id": "automation", "ip": "198.51.100.1", "subnet": "32"}]`)
+		case http.MethodPatch:
+			var body struct {
+				Entries []*gofastly.BatchACLEntry `json:"entries"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("unexpected body: %s", err)
+			}
+			ops = append(ops, body.Entries...)
+			fmt.Fprint(w, `{"status": "ok"}`)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &FastlyClient{conn: conn}
+
+	// The state of the last exclusive refresh holds the entry added by
+	// automation, which isn't declared.
+	r := resourceServiceAclEntriesV1()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"service_id": "123",
+		"acl_id":     "456",
+		"entry": []interface{}{
+			map[string]interface{}{"id": "declared", "ip": "192.0.2.1", "subnet": "32", "comment": "old"},
+			map[string]interface{}{"id": "automation", "ip": "198.51.100.1", "subnet": "32"},
+		},
+		"manage_mode": ManageModeExclusive,
+	})
+	d.SetId("123/456")
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"service_id": "123",
+		"acl_id":     "456",
+		"entry": []interface{}{
+			map[string]interface{}{"ip": "192.0.2.1", "subnet": "32", "comment": "new"},
+		},
+		"manage_mode": ManageModeAdditive,
+	})
+	diff, err := r.Diff(context.Background(), d.State(), config, meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, diags := r.Apply(context.Background(), d.State(), diff, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	for _, op := range ops {
+		if op.Operation == gofastly.DeleteBatchOperation {
+			t.Errorf("unexpected deletion of entry %s", *op.ID)
+		}
+	}
+	if len(ops) != 1 || ops[0].Operation != gofastly.UpdateBatchOperation || *ops[0].ID != "declared" {
+		t.Errorf("expected the declared entry to be updated, got %d operations", len(ops))
+	}
+}
+
+func TestACLCIDRsConfig_limit(t *testing.T) {
+	// Every other address, so that none of them are merged.
+	var cidrs []interface{}
+	for i := 0; i <= gofastly.MaximumACLSize; i++ {
+		cidrs = append(cidrs, fmt.Sprintf("10.%d.%d.%d", i>>15&255, i>>7&255, i<<1&255))
+	}
+
+	_, ok, err := aclCIDRsConfig(schema.NewSet(schema.HashString, cidrs), "")
+	if !ok || err == nil {
+		t.Fatalf("expected an error for %d entries, got %v", len(cidrs), err)
+	}
+
+	// Adjacent addresses are merged under the limit.
+	cidrs = cidrs[:0]
+	for i := 0; i <= gofastly.MaximumACLSize; i++ {
+		cidrs = append(cidrs, fmt.Sprintf("10.0.%d.%d", i>>8&255, i&255))
+	}
+
+	normalized, _, err := aclCIDRsConfig(schema.NewSet(schema.HashString, cidrs), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"10.0.0.0/19", "10.0.32.0/22", "10.0.36.0/23", "10.0.38.0/24", "10.0.39.0/28", "10.0.39.16/32"}; !reflect.DeepEqual(normalized, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\ngot: %#v", expected, normalized)
+	}
+}
+
 func TestAccFastlyServiceAclEntriesV1_create(t *testing.T) {
 	var service gofastly.ServiceDetail
 	serviceName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
@@ -298,6 +432,112 @@ func TestAccFastlyServiceAclEntriesV1_process_1001_entries(t *testing.T) {
 	})
 }
 
+func TestAccFastlyServiceAclEntriesV1_additive_external_entry_is_kept(t *testing.T) {
+	var service gofastly.ServiceDetail
+	serviceName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	aclName := fmt.Sprintf("ACL %s", acctest.RandString(10))
+
+	declared := map[string]interface{}{
+		"id":      "",
+		"ip":      "127.0.0.1",
+		"subnet":  "24",
+		"negated": false,
+		"comment": "ALC Entry 1",
+	}
+	external := map[string]interface{}{
+		"id":      "",
+		"ip":      "192.168.0.1",
+		"subnet":  "16",
+		"negated": false,
+		"comment": "external",
+	}
+
+	config := testAccServiceACLEntriesV1Config_additive(serviceName, aclName, []map[string]interface{}{declared})
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+				),
+			},
+			{
+				PreConfig: func() { createACLEntryThroughApi(t, &service, aclName, external) },
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFastlyServiceAclEntriesV1RemoteState(&service, serviceName, aclName, []map[string]interface{}{declared, external}),
+					resource.TestCheckResourceAttr("fastly_service_acl_entries_v1.entries", "entry.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFastlyServiceAclEntriesV1_cidrs(t *testing.T) {
+	var service gofastly.ServiceDetail
+	serviceName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	aclName := fmt.Sprintf("ACL %s", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceACLEntriesV1Config_cidrs(serviceName, aclName, []string{"192.0.2.0/25", "192.0.2.128/25", "192.0.2.7", "198.51.100.1"}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					testAccCheckFastlyServiceAclEntriesV1RemoteState(&service, serviceName, aclName, []map[string]interface{}{
+						{"id": "", "ip": "192.0.2.0", "subnet": "24", "negated": false},
+						{"id": "", "ip": "198.51.100.1", "subnet": "32", "negated": false},
+					}),
+					resource.TestCheckResourceAttr("fastly_service_acl_entries_v1.entries", "cidrs_entry_count", "2"),
+					resource.TestCheckResourceAttr("fastly_service_acl_entries_v1.entries", "normalized_cidrs.0", "192.0.2.0/24"),
+				),
+			},
+			{
+				Config: testAccServiceACLEntriesV1Config_cidrs(serviceName, aclName, []string{"192.0.2.0/24", "203.0.113.0/24"}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFastlyServiceAclEntriesV1RemoteState(&service, serviceName, aclName, []map[string]interface{}{
+						{"id": "", "ip": "192.0.2.0", "subnet": "24", "negated": false},
+						{"id": "", "ip": "203.0.113.0", "subnet": "24", "negated": false},
+					}),
+					resource.TestCheckResourceAttr("fastly_service_acl_entries_v1.entries", "cidrs_entry_count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func createACLEntryThroughApi(t *testing.T, service *gofastly.ServiceDetail, aclName string, entry map[string]interface{}) {
+	conn := testAccProvider.Meta().(*FastlyClient).conn
+
+	acl, err := conn.GetACL(&gofastly.GetACLInput{
+		ServiceID:      service.ID,
+		ServiceVersion: service.ActiveVersion.Number,
+		Name:           aclName,
+	})
+	if err != nil {
+		t.Fatalf("[ERR] Error looking up ACL records for (%s), version (%v): %s", service.Name, service.ActiveVersion.Number, err)
+	}
+
+	_, err = conn.CreateACLEntry(&gofastly.CreateACLEntryInput{
+		ServiceID: service.ID,
+		ACLID:     acl.ID,
+		IP:        entry["ip"].(string),
+		Subnet:    entry["subnet"].(string),
+		Negated:   entry["negated"].(bool),
+		Comment:   entry["comment"].(string),
+	})
+	if err != nil {
+		t.Fatalf("[ERR] Error creating ACL entry for (%s), ACL (%s): %s", service.Name, acl.ID, err)
+	}
+}
+
 func testAccCheckFastlyServiceAclEntriesV1RemoteState(service *gofastly.ServiceDetail, serviceName, aclName string, expectedEntries []map[string]interface{}) resource.TestCheckFunc {
 
 	return func(s *terraform.State) error {
@@ -413,3 +653,44 @@ resource "fastly_service_v1" "foo" {
 	%s
 }`, aclName, serviceName, domainName, backendName, aclEntries)
 }
+
+func testAccServiceACLEntriesV1Config_additive(serviceName, aclName string, aclEntriesList []map[string]interface{}) string {
+	return strings.Replace(
+		testAccServiceACLEntriesV1Config_one_acl_with_entries(serviceName, aclName, aclEntriesList),
+		"service_id = fastly_service_v1.foo.id",
+		"service_id = fastly_service_v1.foo.id\n\tmanage_mode = \"additive\"",
+		1,
+	)
+}
+
+func testAccServiceACLEntriesV1Config_cidrs(serviceName, aclName string, cidrs []string) string {
+	backendName := fmt.Sprintf("%s.aws.amazon.com", acctest.RandString(3))
+	domainName := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "%s"
+    name    = "tf-testing-backend"
+  }
+
+  acl {
+    name = "%s"
+  }
+
+  force_destroy = true
+}
+
+resource "fastly_service_acl_entries_v1" "entries" {
+  service_id = fastly_service_v1.foo.id
+  acl_id     = {for s in fastly_service_v1.foo.acl : s.name => s.acl_id}["%s"]
+  cidrs      = ["%s"]
+}`, serviceName, domainName, backendName, aclName, aclName, strings.Join(cidrs, `", "`))
+}
@@ -0,0 +1,181 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceServiceActivation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServiceActivationCreate,
+		ReadContext:   resourceServiceActivationRead,
+		UpdateContext: resourceServiceActivationUpdate,
+		DeleteContext: resourceServiceActivationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServiceActivationImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"service_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the service to activate a version of",
+			},
+			"version": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				Description:      "The version to lock and activate. If a different version is found to be active, it is re-activated on the next apply",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+			"previous_version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version that was active before this resource first activated a version. It is re-activated when the resource is destroyed. `0` when there was no active version",
+			},
+		},
+	}
+}
+
+func resourceServiceActivationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	serviceID := d.Get("service_id").(string)
+
+	s, err := conn.GetServiceDetails(&gofastly.GetServiceInput{
+		ID: serviceID,
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := activateServiceVersion(conn, serviceID, d.Get("version").(int)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(serviceID)
+
+	if err := d.Set("previous_version", s.ActiveVersion.Number); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceServiceActivationRead(ctx, d, meta)
+}
+
+func resourceServiceActivationRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	s, err := conn.GetServiceDetails(&gofastly.GetServiceInput{
+		ID: d.Id(),
+	})
+	if err != nil {
+		if e, ok := err.(*gofastly.HTTPError); ok && e.IsNotFound() {
+			log.Printf("[WARN] Service (%s) not found, removing activation from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("service_id", s.ID); err != nil {
+		return diag.FromErr(err)
+	}
+	// Setting the version to the one which is actually active means another
+	// version being activated outside of Terraform shows up as a diff.
+	if err := d.Set("version", s.ActiveVersion.Number); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceServiceActivationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	if d.HasChange("version") {
+		if err := activateServiceVersion(conn, d.Id(), d.Get("version").(int)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceServiceActivationRead(ctx, d, meta)
+}
+
+// resourceServiceActivationDelete re-activates the previous version, provided
+// the version managed by this resource is still the active one.
+func resourceServiceActivationDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	version := d.Get("version").(int)
+	previousVersion := d.Get("previous_version").(int)
+
+	if previousVersion == 0 || previousVersion == version {
+		log.Printf("[INFO] No previous version of Service (%s) to re-activate, leaving version (%d) active", d.Id(), version)
+		return nil
+	}
+
+	s, err := conn.GetServiceDetails(&gofastly.GetServiceInput{
+		ID: d.Id(),
+	})
+	if err != nil {
+		if e, ok := err.(*gofastly.HTTPError); ok && e.IsNotFound() {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if s.ActiveVersion.Number != version {
+		log.Printf("[INFO] Service (%s) has version (%d) active instead of version (%d), not re-activating version (%d)", d.Id(), s.ActiveVersion.Number, version, previousVersion)
+		return nil
+	}
+
+	log.Printf("[DEBUG] Re-activating Fastly Service (%s), Version (%v)", d.Id(), previousVersion)
+	_, err = conn.ActivateVersion(&gofastly.ActivateVersionInput{
+		ServiceID:      d.Id(),
+		ServiceVersion: previousVersion,
+	})
+	if err != nil {
+		return diag.Errorf("[ERR] Error re-activating version (%d): %s", previousVersion, err)
+	}
+
+	return nil
+}
+
+// resourceServiceActivationImport imports the activation of a service by its
+// ID. The previous version is unknown, so nothing is re-activated on destroy.
+func resourceServiceActivationImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	if err := d.Set("service_id", d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// activateServiceVersion locks the version, so it can no longer be modified
+// while it is being promoted, and activates it.
+func activateServiceVersion(conn *gofastly.Client, serviceID string, version int) error {
+	log.Printf("[DEBUG] Locking Fastly Service (%s), Version (%v)", serviceID, version)
+	_, err := conn.LockVersion(&gofastly.LockVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return fmt.Errorf("[ERR] Error locking version (%d): %s", version, err)
+	}
+
+	log.Printf("[DEBUG] Activating Fastly Service (%s), Version (%v)", serviceID, version)
+	_, err = conn.ActivateVersion(&gofastly.ActivateVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return fmt.Errorf("[ERR] Error activating version (%d): %s", version, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package fastly
+
+import (
+	"fmt"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccFastlyServiceActivation_basic(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain1 := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+	domain2 := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceV1Config(name, domain1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					resource.TestCheckResourceAttr("fastly_service_v1.foo", "active_version", "1"),
+				),
+			},
+			{
+				Config: testAccFastlyServiceActivationConfig(name, domain2, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fastly_service_activation.foo", "version", "2"),
+					resource.TestCheckResourceAttr("fastly_service_activation.foo", "previous_version", "1"),
+					testAccCheckServiceActiveVersion("fastly_service_v1.foo", 2),
+				),
+			},
+			{
+				ResourceName:            "fastly_service_activation.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"previous_version"},
+			},
+			// Destroying the activation re-activates the previous version.
+			{
+				Config: testAccFastlyServiceActivationConfig(name, domain2, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceActiveVersion("fastly_service_v1.foo", 1),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckServiceActiveVersion(n string, version int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*FastlyClient).conn
+		service, err := conn.GetServiceDetails(&gofastly.GetServiceInput{
+			ID: rs.Primary.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if service.ActiveVersion.Number != version {
+			return fmt.Errorf("Bad active version, expected (%d), got (%d)", version, service.ActiveVersion.Number)
+		}
+		return nil
+	}
+}
+
+func testAccFastlyServiceActivationConfig(name, domain string, activation bool) string {
+	config := fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  activate      = false
+  force_destroy = true
+}`, name, domain)
+
+	if activation {
+		config += `
+
+resource "fastly_service_activation" "foo" {
+  service_id = fastly_service_v1.foo.id
+  version    = fastly_service_v1.foo.cloned_version
+}`
+	}
+
+	return config
+}
@@ -18,6 +18,7 @@ var computeService = &BaseServiceDefinition{
 		NewServiceHealthCheck(computeAttributes),
 		NewServiceBackend(computeAttributes),
 		NewServiceDirector(computeAttributes),
+		NewServicePool(computeAttributes),
 		NewServiceS3Logging(computeAttributes),
 		NewServicePaperTrail(computeAttributes),
 		NewServiceSumologic(computeAttributes),
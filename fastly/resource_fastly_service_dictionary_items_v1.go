@@ -4,12 +4,45 @@ import (
 	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"log"
+	"reflect"
+	"sort"
 	"strings"
 
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const (
+	// ManageModeExclusive is the manage_mode in which Terraform owns every
+	// entry of a dictionary or an ACL, deleting those it doesn't declare.
+	ManageModeExclusive = "exclusive"
+	// ManageModeAdditive is the manage_mode in which Terraform only owns the
+	// entries it declares, leaving the others alone.
+	ManageModeAdditive = "additive"
+)
+
+// manageModeSchema returns the manage_mode attribute shared by the dictionary
+// items and ACL entries resources.
+func manageModeSchema(entries string) *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Default:          ManageModeExclusive,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{ManageModeExclusive, ManageModeAdditive}, false)),
+		Description:      fmt.Sprintf("Either `%s`, in which case Terraform owns all the %s and deletes those which aren't declared, or `%s`, in which case Terraform only reads, diffs and deletes the declared %s and leaves the others alone, such as those added by automation. Default `%s`", ManageModeExclusive, entries, ManageModeAdditive, entries, ManageModeExclusive),
+	}
+}
+
+// switchedToAdditive reports whether manage_mode was just changed to additive.
+// The entries of the state were then read while exclusive, including those
+// added by automation, so none of them is deleted by the first additive apply.
+func switchedToAdditive(d *schema.ResourceData) bool {
+	o, n := d.GetChange("manage_mode")
+	return o.(string) != ManageModeAdditive && n.(string) == ManageModeAdditive
+}
+
 func resourceServiceDictionaryItemsV1() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceServiceDictionaryItemsV1Create,
@@ -19,6 +52,7 @@ func resourceServiceDictionaryItemsV1() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceServiceDictionaryItemsV1Import,
 		},
+		CustomizeDiff: resourceServiceDictionaryItemsV1CustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"service_id": {
@@ -41,6 +75,35 @@ func resourceServiceDictionaryItemsV1() *schema.Resource {
 				Description:      "A map representing an entry in the dictionary, (key/value)",
 				ValidateDiagFunc: validateDictionaryItems(),
 				Elem:             schema.TypeString,
+				ConflictsWith:    []string{"items_file"},
+			},
+
+			"items_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "The path of a file holding the entries of the dictionary, as a JSON object, CSV rows of a key and a value or a YAML mapping, given by the extension of the file (`.json`, `.csv`, `.yaml` or `.yml`). Only a hash of the entries is kept in the state, instead of every entry",
+				ConflictsWith: []string{"items"},
+			},
+
+			"manage_mode": manageModeSchema("items"),
+
+			"items_file_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A hash of the entries of the dictionary, when they are read from `items_file`",
+			},
+
+			"items_file_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The keys of the entries read from `items_file` when `manage_mode` is `additive`, so that they are deleted once they are no longer declared",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
+			"items_summary": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The number of entries added, changed and removed by the last change of `items_file`",
 			},
 		},
 	}
@@ -51,14 +114,40 @@ func resourceServiceDictionaryItemsV1Create(ctx context.Context, d *schema.Resou
 
 	serviceID := d.Get("service_id").(string)
 	dictionaryID := d.Get("dictionary_id").(string)
+	additive := d.Get("manage_mode").(string) == ManageModeAdditive
+
+	if path := d.Get("items_file").(string); path != "" {
+		items, err := readDictionaryItemsFile(path)
+		if err != nil {
+			return diag.Errorf("Error creating dictionary items: service %s, dictionary %s, %s", serviceID, dictionaryID, err)
+		}
+
+		if _, err := syncDictionaryItems(conn, serviceID, dictionaryID, items, nil, additive); err != nil {
+			return diag.Errorf("Error creating dictionary items: service %s, dictionary %s, %s", serviceID, dictionaryID, err)
+		}
+
+		if err := d.Set("items_file_keys", dictionaryItemsFileKeys(items, additive)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.SetId(fmt.Sprintf("%s/%s", serviceID, dictionaryID))
+		return append(resourceServiceDictionaryItemsV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
+	}
+
 	items := d.Get("items").(map[string]interface{})
 
+	// Items which already exist are taken over when additive.
+	operation := gofastly.CreateBatchOperation
+	if additive {
+		operation = gofastly.UpsertBatchOperation
+	}
+
 	var batchDictionaryItems []*gofastly.BatchDictionaryItem
 
 	for key, val := range items {
 
 		batchDictionaryItems = append(batchDictionaryItems, &gofastly.BatchDictionaryItem{
-			Operation: gofastly.CreateBatchOperation,
+			Operation: operation,
 			ItemKey:   key,
 			ItemValue: val.(string),
 		})
@@ -71,7 +160,7 @@ func resourceServiceDictionaryItemsV1Create(ctx context.Context, d *schema.Resou
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", serviceID, dictionaryID))
-	return resourceServiceDictionaryItemsV1Read(ctx, d, meta)
+	return append(resourceServiceDictionaryItemsV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
 }
 
 func resourceServiceDictionaryItemsV1Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -80,6 +169,47 @@ func resourceServiceDictionaryItemsV1Update(ctx context.Context, d *schema.Resou
 
 	serviceID := d.Get("service_id").(string)
 	dictionaryID := d.Get("dictionary_id").(string)
+	additive := d.Get("manage_mode").(string) == ManageModeAdditive
+
+	// The state only holds a hash of the items read from a file, so they are
+	// diffed against the remote items instead, also when switching between
+	// items and items_file. When additive, the items previously declared in
+	// either are deleted once they are no longer declared.
+	if path := d.Get("items_file").(string); path != "" || d.HasChange("items_file") {
+		if d.HasChanges("items", "items_file", "items_file_hash", "items_file_keys") {
+			items, err := dictionaryItemsConfig(d)
+			if err != nil {
+				return diag.Errorf("Error updating dictionary items: service %s, dictionary %s, %s", serviceID, dictionaryID, err)
+			}
+
+			var owned []string
+			if !switchedToAdditive(d) {
+				ok, _ := d.GetChange("items_file_keys")
+				for _, k := range ok.([]interface{}) {
+					owned = append(owned, k.(string))
+				}
+				oi, _ := d.GetChange("items")
+				for k := range oi.(map[string]interface{}) {
+					owned = append(owned, k)
+				}
+			}
+
+			summary, err := syncDictionaryItems(conn, serviceID, dictionaryID, items, owned, additive)
+			if err != nil {
+				return diag.Errorf("Error updating dictionary items: service %s, dictionary %s, %s", serviceID, dictionaryID, err)
+			}
+			log.Printf("[DEBUG] Updated dictionary items: service %s, dictionary %s, %s", serviceID, dictionaryID, summary)
+
+			if path == "" {
+				items = nil
+			}
+			if err := d.Set("items_file_keys", dictionaryItemsFileKeys(items, additive)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		return append(resourceServiceDictionaryItemsV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
+	}
 
 	if d.HasChange("items") {
 
@@ -90,9 +220,10 @@ func resourceServiceDictionaryItemsV1Update(ctx context.Context, d *schema.Resou
 		os := o.(map[string]interface{})
 		ns := n.(map[string]interface{})
 
-		// Handle Removal
+		// Handle Removal, unless the items of the state were read while
+		// exclusive
 		for key := range os {
-			if _, ok := ns[key]; !ok {
+			if _, ok := ns[key]; !ok && !switchedToAdditive(d) {
 
 				batchDictionaryItems = append(batchDictionaryItems, &gofastly.BatchDictionaryItem{
 					Operation: gofastly.DeleteBatchOperation,
@@ -112,11 +243,16 @@ func resourceServiceDictionaryItemsV1Update(ctx context.Context, d *schema.Resou
 				})
 			}
 
-			// Handle additions
+			// Handle additions, taking over the items which already exist
+			// when additive
 			if _, ok := os[key]; !ok {
+				operation := gofastly.CreateBatchOperation
+				if additive {
+					operation = gofastly.UpsertBatchOperation
+				}
 
 				batchDictionaryItems = append(batchDictionaryItems, &gofastly.BatchDictionaryItem{
-					Operation: gofastly.CreateBatchOperation,
+					Operation: operation,
 					ItemKey:   key,
 					ItemValue: val.(string),
 				})
@@ -131,7 +267,7 @@ func resourceServiceDictionaryItemsV1Update(ctx context.Context, d *schema.Resou
 		}
 	}
 
-	return resourceServiceDictionaryItemsV1Read(ctx, d, meta)
+	return append(resourceServiceDictionaryItemsV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
 }
 
 func resourceServiceDictionaryItemsV1Read(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -148,7 +284,38 @@ func resourceServiceDictionaryItemsV1Read(_ context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 
-	err = d.Set("items", flattenDictionaryItems(dictList))
+	items := flattenDictionaryItems(dictList)
+
+	// Only the declared items are read when additive, those of items_file
+	// being the keys recorded in the state.
+	if d.Get("manage_mode").(string) == ManageModeAdditive {
+		declared := make(map[string]bool)
+		if d.Get("items_file").(string) != "" {
+			for _, k := range d.Get("items_file_keys").([]interface{}) {
+				declared[k.(string)] = true
+			}
+		} else {
+			for k := range d.Get("items").(map[string]interface{}) {
+				declared[k] = true
+			}
+		}
+		for k := range items {
+			if !declared[k] {
+				delete(items, k)
+			}
+		}
+	}
+
+	if d.Get("items_file").(string) != "" {
+		err = d.Set("items_file_hash", hashDictionaryItems(items))
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("items", items)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("items_file_hash", "")
 	return diag.FromErr(err)
 }
 
@@ -157,6 +324,29 @@ func resourceServiceDictionaryItemsV1Delete(_ context.Context, d *schema.Resourc
 
 	serviceID := d.Get("service_id").(string)
 	dictionaryID := d.Get("dictionary_id").(string)
+
+	// The state doesn't hold the items read from a file, so every remote item
+	// is deleted instead, or the remote items of the keys recorded in the state
+	// when additive.
+	if d.Get("items_file").(string) != "" {
+		var err error
+		if d.Get("manage_mode").(string) == ManageModeAdditive {
+			var keys []string
+			for _, k := range d.Get("items_file_keys").([]interface{}) {
+				keys = append(keys, k.(string))
+			}
+			err = deleteDictionaryItems(conn, serviceID, dictionaryID, keys)
+		} else {
+			_, err = syncDictionaryItems(conn, serviceID, dictionaryID, nil, nil, false)
+		}
+		if err != nil {
+			return diag.Errorf("Error deleting dictionary items: service %s, dictionary %s, %s", serviceID, dictionaryID, err)
+		}
+
+		d.SetId("")
+		return nil
+	}
+
 	items := d.Get("items").(map[string]interface{})
 
 	var batchDictionaryItems []*gofastly.BatchDictionaryItem
@@ -198,9 +388,112 @@ func resourceServiceDictionaryItemsV1Import(_ context.Context, d *schema.Resourc
 		return nil, fmt.Errorf("Error importing dictionary items: service %s, dictionary %s, %s", serviceID, dictionaryID, err)
 	}
 
+	err = d.Set("manage_mode", ManageModeExclusive)
+	if err != nil {
+		return nil, fmt.Errorf("Error importing dictionary items: service %s, dictionary %s, %s", serviceID, dictionaryID, err)
+	}
+
 	return []*schema.ResourceData{d}, nil
 }
 
+// resourceServiceDictionaryItemsV1CustomizeDiff plans a change of the items
+// read from items_file as a new hash and a summary of the changes, instead of a
+// diff of every item.
+func resourceServiceDictionaryItemsV1CustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("items_file") {
+		if err := d.SetNewComputed("items_file_hash"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("items_file_keys"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("items_summary")
+	}
+
+	var owned []string
+	for _, k := range d.Get("items_file_keys").([]interface{}) {
+		owned = append(owned, k.(string))
+	}
+
+	path := d.Get("items_file").(string)
+	if path == "" {
+		if d.Get("items_file_hash").(string) != "" {
+			if err := d.SetNew("items_file_hash", ""); err != nil {
+				return err
+			}
+		}
+		if len(owned) > 0 {
+			return d.SetNew("items_file_keys", []string{})
+		}
+		return nil
+	}
+
+	items, err := readDictionaryItemsFile(path)
+	if err != nil {
+		return err
+	}
+	if max := gofastly.MaximumDictionarySize; len(items) > max {
+		return fmt.Errorf("expected %s to hold at most %d items, got %d", path, max, len(items))
+	}
+
+	replaced := d.HasChange("service_id") || d.HasChange("dictionary_id")
+	additive := d.Get("manage_mode").(string) == ManageModeAdditive
+	hash := hashDictionaryItems(items)
+	keys := dictionaryItemsFileKeys(items, additive)
+	sameKeys := (len(keys) == 0 && len(owned) == 0) || reflect.DeepEqual(keys, owned)
+	if hash == d.Get("items_file_hash").(string) && !replaced && sameKeys {
+		return nil
+	}
+
+	summary := dictionaryItemsSummary{added: len(items)}
+	if d.Id() != "" && !replaced {
+		remote, err := meta.(*FastlyClient).conn.ListDictionaryItems(&gofastly.ListDictionaryItemsInput{
+			ServiceID:    d.Get("service_id").(string),
+			DictionaryID: d.Get("dictionary_id").(string),
+		})
+		if err != nil {
+			return err
+		}
+		summary, _ = diffDictionaryItems(remote, items, owned, additive, func(*gofastly.BatchDictionaryItem) error { return nil })
+	}
+
+	if err := d.SetNew("items_file_hash", hash); err != nil {
+		return err
+	}
+	if err := d.SetNew("items_file_keys", keys); err != nil {
+		return err
+	}
+	return d.SetNew("items_summary", summary.String())
+}
+
+// dictionaryItemsFileKeys returns the sorted keys of the items read from
+// items_file, which are owned by the resource when additive.
+func dictionaryItemsFileKeys(items map[string]string, additive bool) []string {
+	keys := []string{}
+	if !additive {
+		return keys
+	}
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dictionaryItemsConfig returns the items of the configuration, read from
+// items_file when it is set.
+func dictionaryItemsConfig(d *schema.ResourceData) (map[string]string, error) {
+	if path := d.Get("items_file").(string); path != "" {
+		return readDictionaryItemsFile(path)
+	}
+
+	items := make(map[string]string)
+	for k, v := range d.Get("items").(map[string]interface{}) {
+		items[k] = v.(string)
+	}
+	return items, nil
+}
+
 func flattenDictionaryItems(dictItemList []*gofastly.DictionaryItem) map[string]string {
 	resultList := make(map[string]string)
 	for _, currentDictItem := range dictItemList {
@@ -1,7 +1,14 @@
 package fastly
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"testing"
@@ -9,6 +16,7 @@ import (
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -47,6 +55,66 @@ func TestResourceFastlyFlattenDictionaryItems(t *testing.T) {
 	}
 }
 
+func TestResourceServiceDictionaryItemsV1Update_switchToAdditive(t *testing.T) {
+	var ops []*gofastly.BatchDictionaryItem
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `[{"item_key": "declared", "item_value": "new"}, {"item_key": "automation", "item_value": "value"}]`)
+		case http.MethodPatch:
+			var body struct {
+				Items []*gofastly.BatchDictionaryItem `json:"items"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("unexpected body: %s", err)
+			}
+			ops = append(ops, body.Items...)
+			fmt.Fprint(w, `{"status": "ok"}`)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &FastlyClient{conn: conn}
+
+	// The state of the last exclusive refresh holds the item added by
+	// automation, which isn't declared.
+	r := resourceServiceDictionaryItemsV1()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"service_id":    "123",
+		"dictionary_id": "456",
+		"items":         map[string]interface{}{"declared": "old", "automation": "value"},
+		"manage_mode":   ManageModeExclusive,
+	})
+	d.SetId("123/456")
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"service_id":    "123",
+		"dictionary_id": "456",
+		"items":         map[string]interface{}{"declared": "new"},
+		"manage_mode":   ManageModeAdditive,
+	})
+	diff, err := r.Diff(context.Background(), d.State(), config, meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, diags := r.Apply(context.Background(), d.State(), diff, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	expected := []*gofastly.BatchDictionaryItem{
+		{Operation: gofastly.UpdateBatchOperation, ItemKey: "declared", ItemValue: "new"},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("Error matching:\nexpected: %#v\n     got: %#v", expected, ops)
+	}
+}
+
 func TestAccFastlyServiceDictionaryItemV1_create(t *testing.T) {
 	var service gofastly.ServiceDetail
 	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
@@ -297,6 +365,93 @@ func TestAccFastlyServiceDictionaryItemV1_batch_1001_items(t *testing.T) {
 	})
 }
 
+func TestAccFastlyServiceDictionaryItemV1_items_file(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	dictName := fmt.Sprintf("dict %s", acctest.RandString(10))
+
+	dir, err := ioutil.TempDir("", "dictionary-items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "items.csv")
+
+	writeItems := func(data string) {
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { writeItems("key,value\nkey1,value1\nkey2,value2\n") },
+				Config:    testAccServiceDictionaryItemsV1Config_items_file(name, dictName, path),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					testAccCheckFastlyServiceDictionaryItemsV1RemoteState(&service, name, dictName, map[string]string{
+						"key1": "value1",
+						"key2": "value2",
+					}),
+					resource.TestCheckResourceAttr("fastly_service_dictionary_items_v1.items", "items.%", "0"),
+					resource.TestCheckResourceAttr("fastly_service_dictionary_items_v1.items", "items_summary", "2 added, 0 changed, 0 removed"),
+				),
+			},
+			{
+				PreConfig: func() { writeItems("key,value\nkey1,value3\nkey4,value4\n") },
+				Config:    testAccServiceDictionaryItemsV1Config_items_file(name, dictName, path),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFastlyServiceDictionaryItemsV1RemoteState(&service, name, dictName, map[string]string{
+						"key1": "value3",
+						"key4": "value4",
+					}),
+					resource.TestCheckResourceAttr("fastly_service_dictionary_items_v1.items", "items_summary", "1 added, 1 changed, 1 removed"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFastlyServiceDictionaryItemV1_additive_external_item_is_kept(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	dictName := fmt.Sprintf("dict %s", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceDictionaryItemsV1Config_additive(name, dictName, map[string]string{
+					"key1": "value1",
+					"key2": "value2",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+				),
+			},
+			{
+				PreConfig: func() { createDictionaryItemThroughApi(t, &service, dictName, "key3", "value3") },
+				Config: testAccServiceDictionaryItemsV1Config_additive(name, dictName, map[string]string{
+					"key1": "value1",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFastlyServiceDictionaryItemsV1RemoteState(&service, name, dictName, map[string]string{
+						"key1": "value1",
+						"key3": "value3",
+					}),
+					resource.TestCheckResourceAttr("fastly_service_dictionary_items_v1.items", "items.%", "1"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckFastlyServiceDictionaryItemsV1DoesNotExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		_, ok := s.RootModule().Resources[n]
@@ -498,3 +653,75 @@ resource "fastly_service_dictionary_items_v1" "common" {
 
 }`, dictName, serviceName, domainName, backendName)
 }
+
+func testAccServiceDictionaryItemsV1Config_items_file(serviceName, dictName, path string) string {
+	backendName := fmt.Sprintf("%s.aws.amazon.com", acctest.RandString(3))
+	domainName := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "%s"
+    name    = "tf -test backend"
+  }
+
+  dictionary {
+    name = "%s"
+  }
+
+  force_destroy = true
+}
+
+resource "fastly_service_dictionary_items_v1" "items" {
+  service_id    = fastly_service_v1.foo.id
+  dictionary_id = {for d in fastly_service_v1.foo.dictionary : d.name => d.dictionary_id}["%s"]
+  items_file    = "%s"
+}`, serviceName, domainName, backendName, dictName, dictName, path)
+}
+
+func testAccServiceDictionaryItemsV1Config_additive(serviceName, dictName string, items map[string]string) string {
+	backendName := fmt.Sprintf("%s.aws.amazon.com", acctest.RandString(3))
+	domainName := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	var dictItems string
+	for key, value := range items {
+		dictItems += fmt.Sprintf("    %s = \"%s\"\n", key, value)
+	}
+
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "%s"
+    name    = "tf -test backend"
+  }
+
+  dictionary {
+    name = "%s"
+  }
+
+  force_destroy = true
+}
+
+resource "fastly_service_dictionary_items_v1" "items" {
+  service_id    = fastly_service_v1.foo.id
+  dictionary_id = {for d in fastly_service_v1.foo.dictionary : d.name => d.dictionary_id}["%s"]
+  manage_mode   = "additive"
+
+  items = {
+%s  }
+}`, serviceName, domainName, backendName, dictName, dictName, dictItems)
+}
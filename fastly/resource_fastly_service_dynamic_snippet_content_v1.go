@@ -19,6 +19,7 @@ func resourceServiceDynamicSnippetContentV1() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceServiceDynamicSnippetContentV1Import,
 		},
+		CustomizeDiff: validateDynamicSnippetContentSyntax,
 
 		Schema: map[string]*schema.Schema{
 			"service_id": {
@@ -64,7 +65,7 @@ func resourceServiceDynamicSnippetV1Create(ctx context.Context, d *schema.Resour
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", serviceID, snippetID))
-	return resourceServiceDynamicSnippetV1Read(ctx, d, meta)
+	return append(resourceServiceDynamicSnippetV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
 }
 
 func resourceServiceDynamicSnippetV1Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -89,7 +90,7 @@ func resourceServiceDynamicSnippetV1Update(ctx context.Context, d *schema.Resour
 		}
 	}
 
-	return resourceServiceDynamicSnippetV1Read(ctx, d, meta)
+	return append(resourceServiceDynamicSnippetV1Read(ctx, d, meta), meta.(*FastlyClient).rateLimitWarning()...)
 }
 
 func resourceServiceDynamicSnippetV1Read(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -0,0 +1,190 @@
+package fastly
+
+import (
+	"context"
+	"log"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	purgeTypeURL = "url"
+	purgeTypeKey = "key"
+	purgeTypeAll = "all"
+)
+
+func resourceServicePurge() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServicePurgeCreate,
+		ReadContext:   resourceServicePurgeRead,
+		DeleteContext: resourceServicePurgeDelete,
+
+		Schema: map[string]*schema.Schema{
+			"service_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the service to purge",
+			},
+			"urls": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ForceNew:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ExactlyOneOf: []string{"urls", "keys", "all"},
+				Description:  "A list of URLs to purge (e.g. `www.example.com/index.html`). A leading `http://` or `https://` scheme is ignored",
+			},
+			"keys": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ForceNew:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ExactlyOneOf: []string{"urls", "keys", "all"},
+				Description:  "A list of surrogate keys to purge",
+			},
+			"all": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"urls", "keys", "all"},
+				Description:  "Set to `true` to purge all content from the service",
+			},
+			"soft": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Marks the content as stale instead of removing it from the cache. Default `false`",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary map of values that, when changed, will trigger the purge to run again (e.g. `active_version = fastly_service_v1.example.active_version`)",
+			},
+			"results": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The outcome of each purge request made",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of purge (`url`, `key` or `all`)",
+						},
+						"target": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The URL or surrogate key which was purged. Empty when purging all content",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status returned by the Fastly API, usually `ok`",
+						},
+						"purge_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique ID of the purge request",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceServicePurgeCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	serviceID := d.Get("service_id").(string)
+	soft := d.Get("soft").(bool)
+
+	var results []map[string]interface{}
+
+	if v, ok := d.GetOk("urls"); ok {
+		for _, u := range v.(*schema.Set).List() {
+			url := stripURLScheme(u.(string))
+
+			log.Printf("[DEBUG] Purging URL (%s) for Fastly Service (%s)", url, serviceID)
+			p, err := conn.Purge(&gofastly.PurgeInput{
+				URL:  url,
+				Soft: soft,
+			})
+			if err != nil {
+				return diag.Errorf("Error purging URL (%s): %s", url, err)
+			}
+
+			results = append(results, flattenPurge(purgeTypeURL, url, p))
+		}
+	}
+
+	if v, ok := d.GetOk("keys"); ok {
+		for _, k := range v.(*schema.Set).List() {
+			key := k.(string)
+
+			log.Printf("[DEBUG] Purging surrogate key (%s) for Fastly Service (%s)", key, serviceID)
+			p, err := conn.PurgeKey(&gofastly.PurgeKeyInput{
+				ServiceID: serviceID,
+				Key:       key,
+				Soft:      soft,
+			})
+			if err != nil {
+				return diag.Errorf("Error purging surrogate key (%s): %s", key, err)
+			}
+
+			results = append(results, flattenPurge(purgeTypeKey, key, p))
+		}
+	}
+
+	if d.Get("all").(bool) {
+		log.Printf("[DEBUG] Purging all content for Fastly Service (%s)", serviceID)
+		p, err := conn.PurgeAll(&gofastly.PurgeAllInput{
+			ServiceID: serviceID,
+			Soft:      soft,
+		})
+		if err != nil {
+			return diag.Errorf("Error purging all content for service (%s): %s", serviceID, err)
+		}
+
+		results = append(results, flattenPurge(purgeTypeAll, "", p))
+	}
+
+	d.SetId(resource.UniqueId())
+
+	if err := d.Set("results", results); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceServicePurgeRead is a no-op as a purge is a one-off action which
+// has no remote state to refresh.
+func resourceServicePurgeRead(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+// resourceServicePurgeDelete only removes the resource from state as a purge
+// cannot be undone.
+func resourceServicePurgeDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+func flattenPurge(purgeType, target string, p *gofastly.Purge) map[string]interface{} {
+	result := map[string]interface{}{
+		"type":   purgeType,
+		"target": target,
+	}
+	if p != nil {
+		result["status"] = p.Status
+		result["purge_id"] = p.ID
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package fastly
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceFastlyFlattenPurge(t *testing.T) {
+	cases := []struct {
+		purgeType string
+		target    string
+		remote    *gofastly.Purge
+		local     map[string]interface{}
+	}{
+		{
+			purgeType: purgeTypeKey,
+			target:    "products",
+			remote: &gofastly.Purge{
+				Status: "ok",
+				ID:     "108-1391560174-974124",
+			},
+			local: map[string]interface{}{
+				"type":     purgeTypeKey,
+				"target":   "products",
+				"status":   "ok",
+				"purge_id": "108-1391560174-974124",
+			},
+		},
+		{
+			purgeType: purgeTypeAll,
+			remote:    nil,
+			local: map[string]interface{}{
+				"type":   purgeTypeAll,
+				"target": "",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		out := flattenPurge(c.purgeType, c.target, c.remote)
+		if !reflect.DeepEqual(out, c.local) {
+			t.Fatalf("Error matching:\nexpected: %#v\n     got: %#v", c.local, out)
+		}
+	}
+}
+
+func TestAccFastlyServicePurge_basic(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServicePurgeConfig(name, domain, "amazon docs"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					resource.TestCheckResourceAttr("fastly_service_purge.keys", "results.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("fastly_service_purge.keys", "results.*", map[string]string{
+						"type":   purgeTypeKey,
+						"target": "products",
+						"status": "ok",
+					}),
+					resource.TestCheckResourceAttr("fastly_service_purge.all", "results.#", "1"),
+					resource.TestCheckResourceAttr("fastly_service_purge.all", "results.0.type", purgeTypeAll),
+					resource.TestCheckResourceAttr("fastly_service_purge.all", "results.0.status", "ok"),
+				),
+			},
+			{
+				// Changing the backend creates a new active version which triggers the purges again.
+				Config: testAccServicePurgeConfig(name, domain, "amazon docs updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					resource.TestCheckResourceAttr("fastly_service_purge.keys", "triggers.active_version", "2"),
+					resource.TestCheckResourceAttr("fastly_service_purge.keys", "results.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccServicePurgeConfig(name, domain, backendName string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "%s"
+  }
+
+  force_destroy = true
+}
+
+resource "fastly_service_purge" "keys" {
+  service_id = fastly_service_v1.foo.id
+  keys       = ["products", "images"]
+  soft       = true
+
+  triggers = {
+    active_version = fastly_service_v1.foo.active_version
+  }
+}
+
+resource "fastly_service_purge" "all" {
+  service_id = fastly_service_v1.foo.id
+  all        = true
+
+  triggers = {
+    active_version = fastly_service_v1.foo.active_version
+  }
+}`, name, domain, backendName)
+}
@@ -20,6 +20,7 @@ var vclService = &BaseServiceDefinition{
 		NewServiceHealthCheck(vclAttributes),
 		NewServiceBackend(vclAttributes),
 		NewServiceDirector(vclAttributes),
+		NewServicePool(vclAttributes),
 		NewServiceHeader(vclAttributes),
 		NewServiceGzip(vclAttributes),
 		NewServiceS3Logging(vclAttributes),
@@ -629,6 +629,73 @@ func TestAccFastlyServiceV1_defaultHost(t *testing.T) {
 	})
 }
 
+func TestAccFastlyServiceV1_staleIfError(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			// service defaults
+			{
+				Config: testAccServiceV1Config_default_host(name, domain, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					resource.TestCheckResourceAttr(
+						"fastly_service_v1.foo", "stale_if_error", "false"),
+					resource.TestCheckResourceAttr(
+						"fastly_service_v1.foo", "stale_if_error_ttl", "43200"),
+				),
+			},
+			// enable stale_if_error with a custom TTL
+			{
+				Config: testAccServiceV1Config_staleIfError(name, domain, true, 86400),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					testAccCheckFastlyServiceV1Attributes_staleIfError(&service, true, 86400),
+					resource.TestCheckResourceAttr(
+						"fastly_service_v1.foo", "stale_if_error", "true"),
+					resource.TestCheckResourceAttr(
+						"fastly_service_v1.foo", "stale_if_error_ttl", "86400"),
+					resource.TestCheckResourceAttr(
+						"fastly_service_v1.foo", "active_version", "2"),
+				),
+			},
+			{
+				ResourceName:            "fastly_service_v1.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"activate", "force_destroy"},
+			},
+		},
+	})
+}
+
+func testAccCheckFastlyServiceV1Attributes_staleIfError(service *gofastly.ServiceDetail, staleIfError bool, staleIfErrorTTL uint) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*FastlyClient).conn
+		settings, err := conn.GetSettings(&gofastly.GetSettingsInput{
+			ServiceID:      service.ID,
+			ServiceVersion: service.ActiveVersion.Number,
+		})
+		if err != nil {
+			return fmt.Errorf("[ERR] Error looking up Version settings for (%s), version (%v): %s", service.Name, service.ActiveVersion.Number, err)
+		}
+
+		if settings.StaleIfError != staleIfError {
+			return fmt.Errorf("Bad stale_if_error, expected (%t), got (%t)", staleIfError, settings.StaleIfError)
+		}
+		if settings.StaleIfErrorTTL != staleIfErrorTTL {
+			return fmt.Errorf("Bad stale_if_error_ttl, expected (%d), got (%d)", staleIfErrorTTL, settings.StaleIfErrorTTL)
+		}
+
+		return nil
+	}
+}
+
 // TestAccFastlyServiceV1_brokenSnippet tests that a service can still be updated after it has failed during an apply.
 // This avoids a bug when activate=true, where setting an invalid snippet causes the resourceServiceUpdate function to
 // return early before activating the version. This broke the assumption that cloned_version always tracks the active
@@ -670,6 +737,58 @@ func TestAccFastlyServiceV1_brokenSnippet(t *testing.T) {
 	})
 }
 
+// TestAccFastlyServiceV1_activationRollback activates a new version whose health check can never succeed and checks
+// that the previously active version is re-activated.
+func TestAccFastlyServiceV1_activationRollback(t *testing.T) {
+	var service gofastly.ServiceDetail
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain1 := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+	domain2 := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceV1Config(name, domain1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceV1Exists("fastly_service_v1.foo", &service),
+					resource.TestCheckResourceAttr("fastly_service_v1.foo", "active_version", "1"),
+				),
+			},
+			{
+				Config:      testAccServiceV1Config_activation(name, domain2, "http://127.0.0.1:1/"),
+				ExpectError: regexp.MustCompile(`Version \(1\) has been re-activated in place of version \(2\)`),
+			},
+			// The state is read back from the re-activated version, whose domain differs from the configuration, so the
+			// change is planned again.
+			{
+				Config:             testAccServiceV1Config_activation(name, domain2, "http://127.0.0.1:1/"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccFastlyServiceV1_activationOnCreate(t *testing.T) {
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccServiceV1Config_activation(name, domain, "http://127.0.0.1:1/"),
+				ExpectError: regexp.MustCompile(`There is no previously active version to re-activate`),
+			},
+		},
+	})
+}
+
 func testAccCheckServiceV1Destroy(s *terraform.State) error {
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "fastly_service_v1" {
@@ -732,6 +851,52 @@ resource "fastly_service_v1" "foo" {
 }`, name, domain, defaultHost)
 }
 
+func testAccServiceV1Config_staleIfError(name, domain string, staleIfError bool, staleIfErrorTTL int) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  stale_if_error     = %t
+  stale_if_error_ttl = %d
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  force_destroy = true
+}`, name, domain, staleIfError, staleIfErrorTTL)
+}
+
+func testAccServiceV1Config_activation(name, domain, healthCheckURL string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+  }
+
+  activation {
+    health_check_url = "%s"
+    timeout          = 10
+  }
+
+  force_destroy = true
+}`, name, domain, healthCheckURL)
+}
+
 func testAccServiceV1Config_basicUpdate(name, comment, versionComment, domain string) string {
 	return fmt.Sprintf(`
 resource "fastly_service_v1" "foo" {
@@ -0,0 +1,163 @@
+package fastly
+
+import (
+	"context"
+	"log"
+
+	"github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceFastlyTLSConfiguration only manages the name of a TLS configuration,
+// the only field UpdateCustomTLSConfiguration of go-fastly can change. The
+// other fields are read-only and refreshed to detect drift.
+func resourceFastlyTLSConfiguration() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manages the name of an existing TLS configuration. The Fastly API only allows the name to be changed, so the other options, such as the HTTP and TLS protocols, are read-only.",
+		CreateContext: resourceFastlyTLSConfigurationCreate,
+		ReadContext:   resourceFastlyTLSConfigurationRead,
+		UpdateContext: resourceFastlyTLSConfigurationUpdate,
+		DeleteContext: resourceFastlyTLSConfigurationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"configuration_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the existing TLS configuration to manage. TLS configurations are provisioned by Fastly and cannot be created through the API.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Custom name of the TLS configuration.",
+			},
+			"tls_protocols": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "TLS protocols available on the TLS configuration. Read-only, as the API can only change the name of a configuration.",
+			},
+			"http_protocols": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "HTTP protocols available on the TLS configuration, e.g. `http/1.1` and `h2`. Read-only, as the API can only change the name of a configuration.",
+			},
+			"tls_service": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Whether the configuration supports the `PLATFORM` or `CUSTOM` TLS service.",
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Signifies whether Fastly will use this configuration as a default when creating a new TLS activation.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp (GMT) when the configuration was created.",
+			},
+			"updated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp (GMT) when the configuration was last updated.",
+			},
+			"dns_records": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "The available DNS addresses that can be used to enable TLS for a domain.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"record_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of DNS record to set, e.g. A, AAAA, or CNAME.",
+						},
+						"record_value": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The IP address or hostname of the DNS record.",
+						},
+						"region": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The regions that will be used to route traffic.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceFastlyTLSConfigurationCreate adopts an existing TLS configuration,
+// as they can't be created through the API, and sets its name.
+func resourceFastlyTLSConfigurationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	configuration, err := conn.UpdateCustomTLSConfiguration(&fastly.UpdateCustomTLSConfigurationInput{
+		ID:   d.Get("configuration_id").(string),
+		Name: d.Get("name").(string),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(configuration.ID)
+
+	return resourceFastlyTLSConfigurationRead(ctx, d, meta)
+}
+
+func resourceFastlyTLSConfigurationRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	configuration, err := conn.GetCustomTLSConfiguration(&fastly.GetCustomTLSConfigurationInput{
+		ID:      d.Id(),
+		Include: "dns_records",
+	})
+	if err != nil {
+		if httpErr, ok := err.(*fastly.HTTPError); ok && httpErr.IsNotFound() {
+			log.Printf("[WARN] TLS configuration (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("configuration_id", configuration.ID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := dataSourceFastlyTLSConfigurationSetAttributes(configuration, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceFastlyTLSConfigurationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	if d.HasChange("name") {
+		_, err := conn.UpdateCustomTLSConfiguration(&fastly.UpdateCustomTLSConfigurationInput{
+			ID:   d.Id(),
+			Name: d.Get("name").(string),
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceFastlyTLSConfigurationRead(ctx, d, meta)
+}
+
+// resourceFastlyTLSConfigurationDelete only removes the configuration from
+// state as TLS configurations can't be deleted through the API.
+func resourceFastlyTLSConfigurationDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	log.Printf("[INFO] TLS configuration (%s) can't be deleted, removing from state only", d.Id())
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,58 @@
+package fastly
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFastlyTLSConfiguration_basic(t *testing.T) {
+	name := acctest.RandomWithPrefix(testResourcePrefix)
+	updatedName := acctest.RandomWithPrefix(testResourcePrefix)
+	resourceName := "fastly_tls_configuration.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFastlyTLSConfigurationConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttrPair(resourceName, "configuration_id", "data.fastly_tls_configuration.default", "id"),
+					resource.TestCheckResourceAttr(resourceName, "tls_service", "CUSTOM"),
+					resource.TestCheckResourceAttrSet(resourceName, "http_protocols.#"),
+					resource.TestCheckResourceAttrSet(resourceName, "tls_protocols.#"),
+					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
+				),
+			},
+			{
+				Config: testAccFastlyTLSConfigurationConfig(updatedName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", updatedName),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccFastlyTLSConfigurationConfig(name string) string {
+	return fmt.Sprintf(`
+data "fastly_tls_configuration" "default" {
+  default     = true
+  tls_service = "CUSTOM"
+}
+
+resource "fastly_tls_configuration" "test" {
+  configuration_id = data.fastly_tls_configuration.default.id
+  name             = "%s"
+}
+`, name)
+}
@@ -0,0 +1,291 @@
+package fastly
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceFastlyToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFastlyTokenCreate,
+		ReadContext:   resourceFastlyTokenRead,
+		DeleteContext: resourceFastlyTokenDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Name of the token",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The login of the user the token is created for. The Fastly API requires the user's credentials to create a token",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "The password of the user the token is created for",
+			},
+			"scope": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          string(gofastly.GlobalScope),
+				Description:      "The scope of the token, as a space-separated list of `global`, `purge_select`, `purge_all` and `global:read`, e.g. `global:read purge_select`. Default `global`",
+				ValidateDiagFunc: validateTokenScope(),
+				DiffSuppressFunc: suppressEquivalentTokenScopes,
+			},
+			"services": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "A list of service IDs to limit the token to. The token has access to all services when omitted",
+			},
+			"expires_at": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Description:      "Time-stamp (RFC 3339) at which the token will expire. The token never expires when omitted",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+				DiffSuppressFunc: suppressEquivalentTimestamps,
+			},
+			"access_token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The secret of the token. Only available after the token has been created",
+			},
+			"user_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the user the token belongs to",
+			},
+			"ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The IP address of the client that created the token",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time-stamp (GMT) when the token was created",
+			},
+			"last_used_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time-stamp (GMT) when the token was last used",
+			},
+		},
+	}
+}
+
+// errTokenForbidden is returned by findToken when a token can't be looked up
+// without superuser permissions, in which case it may still exist.
+var errTokenForbidden = errors.New("the tokens of the customer account can only be listed by a superuser")
+
+func resourceFastlyTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	input := &gofastly.CreateTokenInput{
+		Name:     d.Get("name").(string),
+		Scope:    gofastly.TokenScope(d.Get("scope").(string)),
+		Username: d.Get("username").(string),
+		Password: d.Get("password").(string),
+	}
+
+	if v, ok := d.GetOk("services"); ok {
+		for _, s := range v.(*schema.Set).List() {
+			input.Services = append(input.Services, s.(string))
+		}
+	}
+
+	if v, ok := d.GetOk("expires_at"); ok {
+		expiresAt, err := time.Parse(time.RFC3339, v.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		input.ExpiresAt = &expiresAt
+	}
+
+	token, err := conn.CreateToken(input)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(token.ID)
+
+	// The secret is only returned when the token is created.
+	if err := d.Set("access_token", token.AccessToken); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The token may not be found by Read when it was created for another
+	// user, so the state is first set from the created token.
+	if err := setTokenAttributes(d, token); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceFastlyTokenRead(ctx, d, meta)
+}
+
+func resourceFastlyTokenRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	token, err := findToken(conn, d.Id())
+	if errors.Is(err, errTokenForbidden) {
+		log.Printf("[WARN] Token (%s) can't be looked up without superuser permissions, keeping the state", d.Id())
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if token == nil {
+		log.Printf("[WARN] Token (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err := setTokenAttributes(d, token); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// setTokenAttributes sets the attributes of the state from a token, except for
+// the secret access token.
+func setTokenAttributes(d *schema.ResourceData, token *gofastly.Token) error {
+	if err := d.Set("name", token.Name); err != nil {
+		return err
+	}
+	if err := d.Set("scope", string(token.Scope)); err != nil {
+		return err
+	}
+	if err := d.Set("services", token.Services); err != nil {
+		return err
+	}
+	if err := d.Set("user_id", token.UserID); err != nil {
+		return err
+	}
+	if err := d.Set("ip", token.IP); err != nil {
+		return err
+	}
+	if err := d.Set("created_at", formatOptionalTime(token.CreatedAt)); err != nil {
+		return err
+	}
+	if err := d.Set("last_used_at", formatOptionalTime(token.LastUsedAt)); err != nil {
+		return err
+	}
+	if err := d.Set("expires_at", formatOptionalTime(token.ExpiresAt)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceFastlyTokenDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*FastlyClient).conn
+
+	err := conn.DeleteToken(&gofastly.DeleteTokenInput{
+		TokenID: d.Id(),
+	})
+	if errRes, ok := err.(*gofastly.HTTPError); ok {
+		if errRes.StatusCode != 404 {
+			return diag.FromErr(err)
+		}
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// findToken looks up a token by ID. The Fastly API has no endpoint to get a
+// single token, so the tokens of the current user are searched first followed
+// by the tokens of the whole customer account (which requires superuser
+// permissions). A nil token is returned when it cannot be found, and
+// errTokenForbidden when the customer tokens cannot be listed without those
+// permissions.
+func findToken(conn *gofastly.Client, id string) (*gofastly.Token, error) {
+	tokens, err := conn.ListTokens()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tokens {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+
+	user, err := conn.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err = conn.ListCustomerTokens(&gofastly.ListCustomerTokensInput{
+		CustomerID: user.CustomerID,
+	})
+	if e, ok := err.(*gofastly.HTTPError); ok && e.StatusCode == http.StatusForbidden {
+		return nil, errTokenForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tokens {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// formatOptionalTime formats an optional API time-stamp as RFC 3339, returning
+// an empty string when it is not set.
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// suppressEquivalentTimestamps suppresses the diff between two RFC 3339
+// time-stamps that represent the same instant in different time zones.
+func suppressEquivalentTimestamps(_, old, new string, _ *schema.ResourceData) bool {
+	o, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	n, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return o.Equal(n)
+}
+
+// suppressEquivalentTokenScopes suppresses the diff between two space-separated
+// lists of the same token scopes in a different order.
+func suppressEquivalentTokenScopes(_, old, new string, _ *schema.ResourceData) bool {
+	o := strings.Fields(old)
+	n := strings.Fields(new)
+	sort.Strings(o)
+	sort.Strings(n)
+	return strings.Join(o, " ") == strings.Join(n, " ")
+}
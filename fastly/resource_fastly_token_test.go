@@ -0,0 +1,193 @@
+package fastly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSuppressEquivalentTimestamps(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		old      string
+		new      string
+		suppress bool
+	}{
+		"identical":       {"2030-01-01T00:00:00Z", "2030-01-01T00:00:00Z", true},
+		"other time zone": {"2030-01-01T00:00:00Z", "2030-01-01T01:00:00+01:00", true},
+		"different":       {"2030-01-01T00:00:00Z", "2030-01-02T00:00:00Z", false},
+		"unset":           {"", "2030-01-01T00:00:00Z", false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if actual := suppressEquivalentTimestamps("expires_at", testcase.old, testcase.new, nil); actual != testcase.suppress {
+				t.Errorf("expected %t, actual %t", testcase.suppress, actual)
+			}
+		})
+	}
+}
+
+func TestFormatOptionalTime(t *testing.T) {
+	if actual := formatOptionalTime(nil); actual != "" {
+		t.Errorf("expected empty string, actual %q", actual)
+	}
+
+	ts := time.Date(2030, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+	if actual := formatOptionalTime(&ts); actual != "2030-01-01T00:00:00Z" {
+		t.Errorf("expected %q, actual %q", "2030-01-01T00:00:00Z", actual)
+	}
+}
+
+func TestFindToken_forbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/tokens":
+			fmt.Fprint(w, `[{"id": "other"}]`)
+		case "/current_user":
+			fmt.Fprint(w, `{"id": "user", "customer_id": "customer"}`)
+		case "/customer/customer/tokens":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"msg": "You do not have permission to perform this action"}`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := gofastly.NewClientForEndpoint("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := findToken(conn, "123")
+	if !errors.Is(err, errTokenForbidden) {
+		t.Errorf("expected errTokenForbidden, got %v", err)
+	}
+	if token != nil {
+		t.Errorf("expected no token, got %#v", token)
+	}
+
+	// The state is kept, rather than the token being orphaned.
+	d := schema.TestResourceDataRaw(t, resourceFastlyToken().Schema, map[string]interface{}{
+		"name": "token",
+	})
+	d.SetId("123")
+	if diags := resourceFastlyTokenRead(context.Background(), d, &FastlyClient{conn: conn}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "123" {
+		t.Errorf("expected the token to be kept in state, got ID %q", d.Id())
+	}
+}
+
+func TestSuppressEquivalentTokenScopes(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		old      string
+		new      string
+		suppress bool
+	}{
+		"identical": {"global:read purge_select", "global:read purge_select", true},
+		"reordered": {"purge_select global:read", "global:read purge_select", true},
+		"spaces":    {"global:read  purge_select", "global:read purge_select", true},
+		"different": {"global:read purge_select", "global:read purge_all", false},
+		"added":     {"global:read", "global:read purge_select", false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if actual := suppressEquivalentTokenScopes("scope", testcase.old, testcase.new, nil); actual != testcase.suppress {
+				t.Errorf("expected %t, actual %t", testcase.suppress, actual)
+			}
+		})
+	}
+}
+
+func TestAccFastlyToken_basic(t *testing.T) {
+	username := os.Getenv("FASTLY_TEST_USERNAME")
+	password := os.Getenv("FASTLY_TEST_PASSWORD")
+	if username == "" || password == "" {
+		t.Skip("FASTLY_TEST_USERNAME and FASTLY_TEST_PASSWORD must be set to create tokens")
+	}
+
+	name := acctest.RandomWithPrefix(testResourcePrefix)
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	resourceName := "fastly_token.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckTokenDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTokenConfig(name, username, password, expiresAt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckTokenExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "scope", "purge_select"),
+					resource.TestCheckResourceAttr(resourceName, "expires_at", expiresAt),
+					resource.TestCheckResourceAttrSet(resourceName, "access_token"),
+					resource.TestCheckResourceAttrSet(resourceName, "user_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "created_at"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckTokenExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*FastlyClient).conn
+		token, err := findToken(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return fmt.Errorf("Token (%s) not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckTokenDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "fastly_token" {
+			continue
+		}
+
+		conn := testAccProvider.Meta().(*FastlyClient).conn
+		token, err := findToken(conn, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("[WARN] Error listing tokens when deleting Fastly Token (%s): %s", rs.Primary.ID, err)
+		}
+		if token != nil {
+			return fmt.Errorf("[WARN] Tried deleting Token (%s), but was still found", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccTokenConfig(name, username, password, expiresAt string) string {
+	return fmt.Sprintf(`
+resource "fastly_token" "test" {
+  name       = "%s"
+  username   = "%s"
+  password   = "%s"
+  scope      = "%s"
+  expires_at = "%s"
+}`, name, username, password, gofastly.PurgeSelectScope, expiresAt)
+}
@@ -0,0 +1,160 @@
+package fastly
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryMinWait = 1 * time.Second
+	DefaultRetryMaxWait = 30 * time.Second
+
+	// RateLimitRemainingHeader is the number of requests modifying a service
+	// which can still be made in the current rate limiting window.
+	RateLimitRemainingHeader = "Fastly-RateLimit-Remaining"
+	// RateLimitResetHeader is the time, in seconds since the epoch, at which
+	// the current rate limiting window ends.
+	RateLimitResetHeader = "Fastly-RateLimit-Reset"
+)
+
+// RetryTransport retries requests to the Fastly API which were rate limited or
+// failed with a transient error, waiting between attempts.
+//
+// Rate limited requests are always retried, as they have been rejected before
+// being processed. Server errors and network errors are only retried for
+// idempotent requests, as a create call may have been processed before it
+// failed, and retrying it would create a duplicate. This includes the PUT
+// requests which clone, activate, deactivate or lock a version, as a retried
+// clone creates another draft version.
+type RetryTransport struct {
+	Next       http.RoundTripper
+	MaxRetries int
+	MinWait    time.Duration
+	MaxWait    time.Duration
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.Next.RoundTrip(req)
+		if attempt >= t.MaxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		// The body of the request has been consumed, so a new one is needed for
+		// the next attempt.
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		wait := t.wait(attempt, resp)
+		if err != nil {
+			log.Printf("[DEBUG] %s %s failed, retrying in %s: %s", req.Method, req.URL.Path, wait, err)
+		} else {
+			log.Printf("[DEBUG] %s %s returned %s, retrying in %s", req.Method, req.URL.Path, resp.Status, wait)
+			// The body is drained so that the connection can be reused.
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *RetryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return isIdempotent(req)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	}
+	return false
+}
+
+// wait returns how long to wait before the next attempt. The Retry-After
+// header takes precedence, followed by the end of the rate limiting window when
+// no more requests can be made in it. Otherwise the wait grows exponentially.
+// The wait is at most MaxWait.
+func (t *RetryTransport) wait(attempt int, resp *http.Response) time.Duration {
+	wait := t.MinWait
+	for i := 0; i < attempt && wait < t.MaxWait; i++ {
+		wait *= 2
+	}
+
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			wait = after
+		} else if resp.Header.Get(RateLimitRemainingHeader) == "0" {
+			if reset, err := strconv.ParseInt(resp.Header.Get(RateLimitResetHeader), 10, 64); err == nil {
+				wait = time.Until(time.Unix(reset, 0))
+			}
+		}
+	}
+
+	if wait > t.MaxWait {
+		wait = t.MaxWait
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// nonIdempotentPUTs are the suffixes of the paths of the PUT requests which
+// aren't idempotent, such as cloning a version.
+var nonIdempotentPUTs = []string{"/clone", "/activate", "/deactivate", "/lock"}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	case http.MethodPut:
+		for _, suffix := range nonIdempotentPUTs {
+			if strings.HasSuffix(req.URL.Path, suffix) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
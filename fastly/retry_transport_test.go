@@ -0,0 +1,225 @@
+package fastly
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+)
+
+// testRetryServer is a stand-in for the Fastly API which responds to every
+// request with the next of its statuses, and then with 200.
+type testRetryServer struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	statuses []int
+	headers  http.Header
+	bodies   []string
+}
+
+func newTestRetryServer(headers http.Header, statuses ...int) *testRetryServer {
+	s := &testRetryServer{statuses: statuses, headers: headers}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.bodies = append(s.bodies, string(body))
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		if status != http.StatusOK {
+			for k, v := range s.headers {
+				w.Header()[k] = v
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"service_id": "service", "version": 1, "name": "condition"}`))
+	}))
+	return s
+}
+
+func testRetryConn(t *testing.T, url string, maxRetries int) *gofastly.Client {
+	conn, err := gofastly.NewClientForEndpoint("", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.HTTPClient.Transport = &RetryTransport{
+		Next:       conn.HTTPClient.Transport,
+		MaxRetries: maxRetries,
+		MinWait:    time.Millisecond,
+		MaxWait:    10 * time.Millisecond,
+	}
+	return conn
+}
+
+func TestRetryTransport_getRetriesServerErrors(t *testing.T) {
+	server := newTestRetryServer(nil, http.StatusServiceUnavailable, http.StatusBadGateway)
+	defer server.Close()
+
+	conn := testRetryConn(t, server.URL, 3)
+	_, err := conn.GetCondition(&gofastly.GetConditionInput{
+		ServiceID:      "service",
+		ServiceVersion: 1,
+		Name:           "condition",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.bodies) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(server.bodies))
+	}
+}
+
+func TestRetryTransport_maxRetries(t *testing.T) {
+	server := newTestRetryServer(nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	conn := testRetryConn(t, server.URL, 2)
+	_, err := conn.GetCondition(&gofastly.GetConditionInput{
+		ServiceID:      "service",
+		ServiceVersion: 1,
+		Name:           "condition",
+	})
+	if e, ok := err.(*gofastly.HTTPError); !ok || e.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a %d error, got %v", http.StatusServiceUnavailable, err)
+	}
+	if len(server.bodies) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(server.bodies))
+	}
+}
+
+func TestRetryTransport_createRetriesRateLimit(t *testing.T) {
+	server := newTestRetryServer(http.Header{"Retry-After": []string{"0"}}, http.StatusTooManyRequests)
+	defer server.Close()
+
+	conn := testRetryConn(t, server.URL, 3)
+	_, err := conn.CreateCondition(&gofastly.CreateConditionInput{
+		ServiceID:      "service",
+		ServiceVersion: 1,
+		Name:           "condition",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The body of the request must be sent again with the retry.
+	if len(server.bodies) != 2 || server.bodies[0] == "" || server.bodies[0] != server.bodies[1] {
+		t.Errorf("Expected the same body to be sent twice, got %#v", server.bodies)
+	}
+}
+
+func TestRetryTransport_createDoesNotRetryServerErrors(t *testing.T) {
+	server := newTestRetryServer(nil, http.StatusInternalServerError)
+	defer server.Close()
+
+	conn := testRetryConn(t, server.URL, 3)
+	_, err := conn.CreateCondition(&gofastly.CreateConditionInput{
+		ServiceID:      "service",
+		ServiceVersion: 1,
+		Name:           "condition",
+	})
+	if e, ok := err.(*gofastly.HTTPError); !ok || e.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected a %d error, got %v", http.StatusInternalServerError, err)
+	}
+	if len(server.bodies) != 1 {
+		t.Errorf("Expected 1 request, got %d", len(server.bodies))
+	}
+}
+
+func TestRetryTransport_cloneDoesNotRetryServerErrors(t *testing.T) {
+	server := newTestRetryServer(nil, http.StatusInternalServerError)
+	defer server.Close()
+
+	conn := testRetryConn(t, server.URL, 3)
+	_, err := conn.CloneVersion(&gofastly.CloneVersionInput{
+		ServiceID:      "service",
+		ServiceVersion: 1,
+	})
+	if e, ok := err.(*gofastly.HTTPError); !ok || e.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected a %d error, got %v", http.StatusInternalServerError, err)
+	}
+	if len(server.bodies) != 1 {
+		t.Errorf("Expected 1 request, got %d", len(server.bodies))
+	}
+}
+
+func TestRetryTransport_updateRetriesServerErrors(t *testing.T) {
+	server := newTestRetryServer(nil, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	conn := testRetryConn(t, server.URL, 3)
+	_, err := conn.UpdateCondition(&gofastly.UpdateConditionInput{
+		ServiceID:      "service",
+		ServiceVersion: 1,
+		Name:           "condition",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.bodies) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(server.bodies))
+	}
+}
+
+func TestRetryTransport_wait(t *testing.T) {
+	transport := &RetryTransport{
+		MinWait: time.Second,
+		MaxWait: 30 * time.Second,
+	}
+
+	reset := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	rateLimit := func(remaining string) http.Header {
+		h := http.Header{}
+		h.Set(RateLimitRemainingHeader, remaining)
+		h.Set(RateLimitResetHeader, reset)
+		return h
+	}
+
+	cases := []struct {
+		name     string
+		attempt  int
+		header   http.Header
+		expected time.Duration
+	}{
+		{"first attempt", 0, http.Header{}, time.Second},
+		{"exponential backoff", 2, http.Header{}, 4 * time.Second},
+		{"at most the maximum", 10, http.Header{}, 30 * time.Second},
+		{"retry after seconds", 0, http.Header{"Retry-After": []string{"7"}}, 7 * time.Second},
+		{"retry after above the maximum", 0, http.Header{"Retry-After": []string{"120"}}, 30 * time.Second},
+		{"rate limit reset", 0, rateLimit("0"), 30 * time.Second},
+		{"rate limit remaining", 1, rateLimit("10"), 2 * time.Second},
+	}
+
+	for _, c := range cases {
+		wait := transport.wait(c.attempt, &http.Response{Header: c.header})
+		if !reflect.DeepEqual(wait, c.expected) {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, wait)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("3"); !ok || wait != 3*time.Second {
+		t.Errorf("Expected 3s, got %s", wait)
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if wait, ok := parseRetryAfter(date); !ok || wait < 59*time.Minute || wait > time.Hour {
+		t.Errorf("Expected about 1h, got %s", wait)
+	}
+
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Errorf("Expected an invalid Retry-After to be ignored")
+	}
+}
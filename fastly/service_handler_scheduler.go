@@ -0,0 +1,252 @@
+package fastly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// DefaultParallelism is the default maximum number of service attribute
+// handlers run concurrently. Handlers are run one at a time by default, as
+// go-fastly leaves the semantics of concurrent modifications of a service
+// undefined.
+const DefaultParallelism = 1
+
+// serviceAttributeDependencies lists the blocks which must be processed before
+// a block, as it references them by name. Every block also depends on
+// conditions, which can be referenced from most blocks.
+var serviceAttributeDependencies = map[string][]string{
+	"backend":  {"healthcheck"},
+	"pool":     {"healthcheck"},
+	"director": {"backend"},
+	"waf":      {"response_object"},
+}
+
+// keyedServiceAttribute is implemented by the attribute handlers embedding
+// DefaultServiceAttributeHandler.
+type keyedServiceAttribute interface {
+	GetKey() string
+}
+
+// serviceAttributeKey returns the key of an attribute handler, or an empty
+// string when the handler does not have one.
+func serviceAttributeKey(a ServiceAttributeDefinition) string {
+	if k, ok := a.(keyedServiceAttribute); ok {
+		return k.GetKey()
+	}
+	return ""
+}
+
+// serviceHandlerDependencies returns, for every handler, the indexes of the
+// handlers which must complete before it runs.
+//
+// Dependencies are only taken from earlier handlers, so the order in which the
+// handlers are defined remains a valid order to run them in. Handlers without
+// a key, such as the settings, may use any attribute and are run on their own.
+func serviceHandlerDependencies(handlers []ServiceAttributeDefinition) [][]int {
+	deps := make([][]int, len(handlers))
+	for i, a := range handlers {
+		key := serviceAttributeKey(a)
+		for j := 0; j < i; j++ {
+			depKey := serviceAttributeKey(handlers[j])
+			if key == "" || depKey == "" || dependsOnServiceAttribute(key, depKey) {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+	return deps
+}
+
+func dependsOnServiceAttribute(key, depKey string) bool {
+	if depKey == "condition" {
+		return true
+	}
+	for _, k := range serviceAttributeDependencies[key] {
+		if k == depKey {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceHandlerFunc runs a single attribute handler using the given API
+// client.
+type serviceHandlerFunc func(a ServiceAttributeDefinition, conn *gofastly.Client) error
+
+// serviceHandlerResult is the outcome of running the handler at index.
+type serviceHandlerResult struct {
+	index int
+	err   error
+}
+
+// runServiceHandlers runs the attribute handlers, up to parallelism of them
+// concurrently, once the handlers they depend on have completed. Handlers are
+// started in the order they are defined in, so a parallelism of 1 runs them
+// one after another as before.
+//
+// The ResourceData shared by the handlers is not safe for concurrent use, so
+// handlers hold a lock while they run, which is only released while they wait
+// for a response from the Fastly API. Handlers must therefore only make
+// requests from the goroutine they are run in. Every handler gets its own API
+// client, as a client serializes all its requests modifying a service.
+//
+// Errors of all the handlers are returned together. Handlers depending on a
+// failed handler are skipped. If the context is cancelled, no further handlers
+// are started, and nil is returned once the running ones have completed.
+func runServiceHandlers(ctx context.Context, client *FastlyClient, handlers []ServiceAttributeDefinition, run serviceHandlerFunc) diag.Diagnostics {
+	parallelism := client.parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	deps := serviceHandlerDependencies(handlers)
+	dependents := make([][]int, len(handlers))
+	pending := make([]int, len(handlers))
+	var ready []int
+	for i := range handlers {
+		pending[i] = len(deps[i])
+		for _, j := range deps[i] {
+			dependents[j] = append(dependents[j], i)
+		}
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	lock := make(serviceHandlerLock, 1)
+	results := make(chan serviceHandlerResult)
+	skipped := make([]bool, len(handlers))
+	var diags diag.Diagnostics
+	var running int
+
+	start := func(i int) {
+		running++
+		go func() {
+			lock.Lock()
+			conn, err := client.serviceHandlerConn(lock)
+			if err == nil {
+				err = run(handlers[i], conn)
+			}
+			lock.Unlock()
+
+			results <- serviceHandlerResult{index: i, err: err}
+		}()
+	}
+
+	var skip func(i int)
+	skip = func(i int) {
+		for _, j := range dependents[i] {
+			if !skipped[j] {
+				skipped[j] = true
+				log.Printf("[DEBUG] Skipping %s as %s failed", serviceAttributeName(handlers[j]), serviceAttributeName(handlers[i]))
+				skip(j)
+			}
+		}
+	}
+
+	for {
+		for running < parallelism && len(ready) > 0 && ctx.Err() == nil {
+			start(ready[0])
+			ready = ready[1:]
+		}
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+
+		if r.err != nil {
+			diags = append(diags, serviceHandlerDiagnostic(handlers[r.index], r.err))
+			skip(r.index)
+			continue
+		}
+
+		for _, j := range dependents[r.index] {
+			pending[j]--
+			if pending[j] == 0 && !skipped[j] {
+				ready = append(ready, j)
+			}
+		}
+		sort.Ints(ready)
+	}
+
+	if err := ctx.Err(); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+func serviceAttributeName(a ServiceAttributeDefinition) string {
+	if key := serviceAttributeKey(a); key != "" {
+		return key
+	}
+	return fmt.Sprintf("%T", a)
+}
+
+func serviceHandlerDiagnostic(a ServiceAttributeDefinition, err error) diag.Diagnostic {
+	d := diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  err.Error(),
+	}
+	if key := serviceAttributeKey(a); key != "" {
+		d.AttributePath = cty.Path{cty.GetAttrStep{Name: key}}
+	}
+	return d
+}
+
+// serviceHandlerLock serializes the attribute handlers run concurrently by
+// runServiceHandlers. It is a channel rather than a mutex, as it is released
+// and re-acquired by the transport of the API client on behalf of a handler.
+type serviceHandlerLock chan struct{}
+
+func (l serviceHandlerLock) Lock() {
+	l <- struct{}{}
+}
+
+func (l serviceHandlerLock) Unlock() {
+	<-l
+}
+
+// serviceHandlerTransport releases the lock of the attribute handler making a
+// request for as long as the request is in flight.
+type serviceHandlerTransport struct {
+	lock serviceHandlerLock
+	next http.RoundTripper
+}
+
+func (t *serviceHandlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lock.Unlock()
+	defer t.lock.Lock()
+	return t.next.RoundTrip(req)
+}
+
+// serviceHandlerConn returns an API client for an attribute handler which
+// releases lock while a request is in flight. It shares the transport of conn.
+func (c *FastlyClient) serviceHandlerConn(lock serviceHandlerLock) (*gofastly.Client, error) {
+	conn, err := gofastly.NewClientForEndpoint(c.apiKey, c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := *c.conn.HTTPClient
+	next := httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	httpClient.Transport = &serviceHandlerTransport{lock: lock, next: next}
+	conn.HTTPClient = &httpClient
+
+	return conn, nil
+}
@@ -0,0 +1,251 @@
+package fastly
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type testServiceAttributeHandler struct {
+	*DefaultServiceAttributeHandler
+	process func(conn *gofastly.Client) error
+}
+
+func newTestServiceAttributeHandler(key string, process func(conn *gofastly.Client) error) ServiceAttributeDefinition {
+	return &testServiceAttributeHandler{
+		&DefaultServiceAttributeHandler{key: key},
+		process,
+	}
+}
+
+func (h *testServiceAttributeHandler) Register(*schema.Resource) error {
+	return nil
+}
+
+func (h *testServiceAttributeHandler) Read(*schema.ResourceData, *gofastly.ServiceDetail, *gofastly.Client) error {
+	return nil
+}
+
+func (h *testServiceAttributeHandler) Process(_ *schema.ResourceData, _ int, conn *gofastly.Client) error {
+	return h.process(conn)
+}
+
+func testServiceHandlerClient(t *testing.T, url string, parallelism int) *FastlyClient {
+	conn, err := gofastly.NewClientForEndpoint("", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &FastlyClient{
+		conn:        conn,
+		baseURL:     url,
+		parallelism: parallelism,
+	}
+}
+
+func runTestServiceHandlers(ctx context.Context, client *FastlyClient, handlers []ServiceAttributeDefinition) error {
+	diags := runServiceHandlers(ctx, client, handlers, func(a ServiceAttributeDefinition, conn *gofastly.Client) error {
+		return a.Process(nil, 1, conn)
+	})
+	if diags.HasError() {
+		var msg string
+		for _, d := range diags {
+			msg += d.Summary + ";"
+		}
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func TestServiceHandlerDependencies(t *testing.T) {
+	handlers := []ServiceAttributeDefinition{
+		NewServiceSettings(),
+		NewServiceCondition(vclAttributes),
+		NewServiceDomain(vclAttributes),
+		NewServiceHealthCheck(vclAttributes),
+		NewServiceBackend(vclAttributes),
+		NewServiceDirector(vclAttributes),
+		NewServiceS3Logging(vclAttributes),
+		NewServiceSyslog(vclAttributes),
+	}
+
+	expected := [][]int{
+		nil,
+		{0},
+		{0, 1},
+		{0, 1},
+		{0, 1, 3},
+		{0, 1, 4},
+		{0, 1},
+		{0, 1},
+	}
+
+	if deps := serviceHandlerDependencies(handlers); !reflect.DeepEqual(deps, expected) {
+		t.Fatalf("Error matching dependencies:\nexpected: %#v\n     got: %#v", expected, deps)
+	}
+}
+
+func TestRunServiceHandlers_order(t *testing.T) {
+	var order []string
+	record := func(key string) ServiceAttributeDefinition {
+		return newTestServiceAttributeHandler(key, func(*gofastly.Client) error {
+			order = append(order, key)
+			return nil
+		})
+	}
+
+	handlers := []ServiceAttributeDefinition{
+		record("condition"),
+		record("healthcheck"),
+		record("backend"),
+		record("director"),
+		record("logging_syslog"),
+		record("logging_s3"),
+	}
+
+	client := testServiceHandlerClient(t, "http://localhost", 1)
+	if err := runTestServiceHandlers(context.Background(), client, handlers); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"condition", "healthcheck", "backend", "director", "logging_syslog", "logging_s3"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Fatalf("Error matching order:\nexpected: %#v\n     got: %#v", expected, order)
+	}
+}
+
+func TestRunServiceHandlers_concurrency(t *testing.T) {
+	var (
+		lock        sync.Mutex
+		inFlight    int
+		maxInFlight int
+	)
+	// Requests are held until as many as the parallelism are in flight, which
+	// only happens if handlers release the lock while waiting for a response.
+	const parallelism = 3
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		if inFlight == parallelism {
+			close(release)
+		}
+		lock.Unlock()
+
+		<-release
+
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	var completed []string
+	request := func(key string) ServiceAttributeDefinition {
+		return newTestServiceAttributeHandler(key, func(conn *gofastly.Client) error {
+			resp, err := conn.Get("/", nil)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			// Completion is recorded without synchronization, so the race
+			// detector also checks the handlers are serialized.
+			completed = append(completed, key)
+			return nil
+		})
+	}
+
+	handlers := []ServiceAttributeDefinition{
+		request("logging_s3"),
+		request("logging_syslog"),
+		request("logging_splunk"),
+		request("logging_kafka"),
+		request("logging_ftp"),
+	}
+
+	client := testServiceHandlerClient(t, server.URL, parallelism)
+	if err := runTestServiceHandlers(context.Background(), client, handlers); err != nil {
+		t.Fatal(err)
+	}
+
+	if maxInFlight != parallelism {
+		t.Errorf("Expected %d concurrent requests, got %d", parallelism, maxInFlight)
+	}
+	if len(completed) != len(handlers) {
+		t.Errorf("Expected %d handlers to complete, got %d", len(handlers), len(completed))
+	}
+}
+
+func TestRunServiceHandlers_errors(t *testing.T) {
+	var ran []string
+	record := func(key string, err error) ServiceAttributeDefinition {
+		return newTestServiceAttributeHandler(key, func(*gofastly.Client) error {
+			ran = append(ran, key)
+			return err
+		})
+	}
+
+	handlers := []ServiceAttributeDefinition{
+		record("healthcheck", errors.New("healthcheck failed")),
+		record("backend", nil),
+		record("director", nil),
+		record("logging_s3", errors.New("logging_s3 failed")),
+		record("logging_syslog", nil),
+	}
+
+	client := testServiceHandlerClient(t, "http://localhost", 1)
+	diags := runServiceHandlers(context.Background(), client, handlers, func(a ServiceAttributeDefinition, conn *gofastly.Client) error {
+		return a.Process(nil, 1, conn)
+	})
+
+	var summaries []string
+	for _, d := range diags {
+		summaries = append(summaries, d.Summary)
+	}
+	expectedSummaries := []string{"healthcheck failed", "logging_s3 failed"}
+	if !reflect.DeepEqual(summaries, expectedSummaries) {
+		t.Errorf("Error matching diagnostics:\nexpected: %#v\n     got: %#v", expectedSummaries, summaries)
+	}
+
+	// The backend and director depend on the failed healthcheck.
+	expectedRan := []string{"healthcheck", "logging_s3", "logging_syslog"}
+	if !reflect.DeepEqual(ran, expectedRan) {
+		t.Errorf("Error matching handlers run:\nexpected: %#v\n     got: %#v", expectedRan, ran)
+	}
+}
+
+func TestRunServiceHandlers_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var ran []string
+	handlers := []ServiceAttributeDefinition{
+		newTestServiceAttributeHandler("condition", func(*gofastly.Client) error {
+			ran = append(ran, "condition")
+			cancel()
+			return nil
+		}),
+		newTestServiceAttributeHandler("logging_s3", func(*gofastly.Client) error {
+			ran = append(ran, "logging_s3")
+			return nil
+		}),
+	}
+
+	client := testServiceHandlerClient(t, "http://localhost", 1)
+	if err := runTestServiceHandlers(ctx, client, handlers); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"condition"}; !reflect.DeepEqual(ran, expected) {
+		t.Fatalf("Error matching handlers run:\nexpected: %#v\n     got: %#v", expected, ran)
+	}
+}
@@ -0,0 +1,125 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// shieldBlocks are the service blocks which have a shield attribute.
+var shieldBlocks = []string{"backend", "director", "pool"}
+
+// validateShields is a CustomizeDiffFunc which checks that the shield of every
+// backend, director and pool is a valid Fastly datacenter. Otherwise an invalid
+// shield is only reported by the API once a new version has been cloned.
+//
+// The check is skipped when the provider has no credentials or the list of
+// datacenters cannot be fetched, as the API will still reject invalid values.
+func validateShields(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	client, ok := meta.(*FastlyClient)
+	if !ok || client == nil || client.noAuth {
+		return nil
+	}
+
+	var changed bool
+	for _, key := range shieldBlocks {
+		if _, ok := d.Get(key).(*schema.Set); ok && d.HasChange(key) && d.NewValueKnown(key) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	datacenters, err := client.allDatacenters()
+	if err != nil {
+		log.Printf("[WARN] Unable to list Fastly datacenters, skipping validation of shields: %s", err)
+		return nil
+	}
+
+	var errs []string
+	for _, key := range shieldBlocks {
+		set, ok := d.Get(key).(*schema.Set)
+		if !ok || !d.NewValueKnown(key) {
+			continue
+		}
+		for _, e := range set.List() {
+			resource := e.(map[string]interface{})
+			if msg := checkShield(key, resource["name"].(string), resource["shield"].(string), datacenters); msg != "" {
+				errs = append(errs, msg)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// checkShield returns an error message if the shield is not a valid Fastly
+// datacenter, suggesting the closest valid value.
+func checkShield(block, name, shield string, datacenters []gofastly.Datacenter) string {
+	if shield == "" {
+		return ""
+	}
+
+	var closest string
+	distance := -1
+	for _, dc := range datacenters {
+		if dc.Shield == "" {
+			continue
+		}
+		if dc.Shield == shield {
+			return ""
+		}
+		if d := levenshtein(strings.ToLower(shield), dc.Shield); distance < 0 || d < distance {
+			closest = dc.Shield
+			distance = d
+		}
+	}
+
+	msg := fmt.Sprintf("%s (%s): shield %q is not a valid Fastly datacenter", block, name, shield)
+	if closest != "" {
+		msg += fmt.Sprintf(", did you mean %q?", closest)
+	}
+	return msg
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
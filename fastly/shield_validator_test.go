@@ -0,0 +1,114 @@
+package fastly
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	gofastly "github.com/fastly/go-fastly/v3/fastly"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestCheckShield(t *testing.T) {
+	datacenters := []gofastly.Datacenter{
+		{Code: "SJC", Shield: "sjc-ca-us"},
+		{Code: "LCY", Shield: "london_city-uk"},
+		{Code: "XYZ", Shield: ""},
+	}
+
+	cases := map[string]struct {
+		shield   string
+		expected string
+	}{
+		"empty shield": {
+			shield:   "",
+			expected: "",
+		},
+		"valid shield": {
+			shield:   "london_city-uk",
+			expected: "",
+		},
+		"misspelled shield": {
+			shield:   "london-city-uk",
+			expected: `backend (amazon docs): shield "london-city-uk" is not a valid Fastly datacenter, did you mean "london_city-uk"?`,
+		},
+		"upper case shield": {
+			shield:   "SJC-CA-US",
+			expected: `backend (amazon docs): shield "SJC-CA-US" is not a valid Fastly datacenter, did you mean "sjc-ca-us"?`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if msg := checkShield("backend", "amazon docs", c.shield, datacenters); msg != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, msg)
+			}
+		})
+	}
+}
+
+func TestCheckShield_noDatacenters(t *testing.T) {
+	expected := `director (mydirector): shield "sjc-ca-us" is not a valid Fastly datacenter`
+	if msg := checkShield("director", "mydirector", "sjc-ca-us", nil); msg != expected {
+		t.Errorf("expected %q, got %q", expected, msg)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"sjc-ca-us", "sjc-ca-us", 0},
+		{"kitten", "sitting", 3},
+		{"london-city-uk", "london_city-uk", 1},
+	}
+
+	for _, c := range cases {
+		if d := levenshtein(c.a, c.b); d != c.expected {
+			t.Errorf("levenshtein(%q, %q): expected %d, got %d", c.a, c.b, c.expected, d)
+		}
+	}
+}
+
+func TestAccFastlyServiceV1_invalidShield(t *testing.T) {
+	name := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	domain := fmt.Sprintf("fastly-test.tf-%s.com", acctest.RandString(10))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      testAccCheckServiceV1Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccServiceV1Config_invalidShield(name, domain),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`backend \(amazon docs\): shield "sjc-ca-uss" is not a valid Fastly datacenter, did you mean "sjc-ca-us"\?`),
+			},
+		},
+	})
+}
+
+func testAccServiceV1Config_invalidShield(name, domain string) string {
+	return fmt.Sprintf(`
+resource "fastly_service_v1" "foo" {
+  name = "%s"
+
+  domain {
+    name    = "%s"
+    comment = "tf-testing-domain"
+  }
+
+  backend {
+    address = "aws.amazon.com"
+    name    = "amazon docs"
+    shield  = "sjc-ca-uss"
+  }
+
+  force_destroy = true
+}`, name, domain)
+}
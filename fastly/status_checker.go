@@ -69,3 +69,63 @@ func (c *WAFDeploymentChecker) waitForDeployment(ctx context.Context, wafID stri
 	}
 	return nil
 }
+
+const (
+	ServiceVersionPending = "pending"
+	ServiceVersionReady   = "ready"
+)
+
+type ServiceVersionStatusCheck func(serviceID string, version int) (*gofastly.Version, error)
+
+// ServiceVersionChecker waits for a newly cloned service version to be
+// available, as new versions are not immediately found in the API or are not
+// immediately mutable.
+type ServiceVersionChecker struct {
+	Timeout    time.Duration
+	Delay      time.Duration
+	MinTimeout time.Duration
+	Check      ServiceVersionStatusCheck
+}
+
+func DefaultServiceVersionChecker(conn *gofastly.Client) func(serviceID string, version int) (*gofastly.Version, error) {
+	checkVersionStatus := func(serviceID string, version int) (*gofastly.Version, error) {
+		return conn.GetVersion(&gofastly.GetVersionInput{
+			ServiceID:      serviceID,
+			ServiceVersion: version,
+		})
+	}
+	return checkVersionStatus
+}
+
+func (c *ServiceVersionChecker) waitForVersion(ctx context.Context, serviceID string, version int) error {
+	versionStateConf := &resource.StateChangeConf{
+		Pending: []string{
+			ServiceVersionPending,
+		},
+		Target: []string{
+			ServiceVersionReady,
+		},
+		Refresh: func() (interface{}, string, error) {
+			res, err := c.Check(serviceID, version)
+			if err != nil {
+				if e, ok := err.(*gofastly.HTTPError); ok && e.IsNotFound() {
+					return version, ServiceVersionPending, nil
+				}
+				return nil, "", err
+			}
+			if res.Locked {
+				return res, ServiceVersionPending, nil
+			}
+			return res, ServiceVersionReady, nil
+		},
+		Timeout:    c.Timeout,
+		Delay:      c.Delay,
+		MinTimeout: c.MinTimeout,
+	}
+
+	_, err := versionStateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("Error waiting for Service (%s) Version (%d) to be available: %v", serviceID, version, err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	gofastly "github.com/fastly/go-fastly/v3/fastly"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -56,3 +57,73 @@ func TestAccFastlyServiceWAFVersionDeploymentStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceVersionChecker(t *testing.T) {
+	notFound := &gofastly.HTTPError{StatusCode: 404}
+	serverError := &gofastly.HTTPError{StatusCode: 500}
+
+	cases := map[string]struct {
+		responses   []*gofastly.Version
+		errors      []error
+		ExpectError bool
+	}{
+		"ready": {
+			responses: []*gofastly.Version{{Number: 2}},
+			errors:    []error{nil},
+		},
+		"not found then locked then ready": {
+			responses: []*gofastly.Version{nil, {Number: 2, Locked: true}, {Number: 2}},
+			errors:    []error{notFound, nil, nil},
+		},
+		"error": {
+			responses:   []*gofastly.Version{nil},
+			errors:      []error{serverError},
+			ExpectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			checker := &ServiceVersionChecker{
+				Timeout:    10 * time.Second,
+				MinTimeout: 0,
+				Delay:      0,
+				Check: func(serviceID string, version int) (*gofastly.Version, error) {
+					i := calls
+					if i >= len(c.responses) {
+						i = len(c.responses) - 1
+					}
+					calls++
+					return c.responses[i], c.errors[i]
+				},
+			}
+			err := checker.waitForVersion(context.Background(), "service-id", 2)
+			hasErrored := err != nil
+			if c.ExpectError && !hasErrored {
+				t.Fatalf("Error expected to be %v but wasn't", c.ExpectError)
+			}
+			if !c.ExpectError && hasErrored {
+				t.Fatalf("Error expected to be %v but wasn't. Error: %v", c.ExpectError, err)
+			}
+			if !c.ExpectError && calls != len(c.responses) {
+				t.Errorf("expected %d checks, got %d", len(c.responses), calls)
+			}
+		})
+	}
+}
+
+func TestServiceVersionChecker_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checker := &ServiceVersionChecker{
+		Timeout: 10 * time.Second,
+		Check: func(serviceID string, version int) (*gofastly.Version, error) {
+			return &gofastly.Version{Number: version, Locked: true}, nil
+		},
+	}
+	if err := checker.waitForVersion(ctx, "service-id", 2); err == nil {
+		t.Fatal("expected an error when the context is cancelled")
+	}
+}
@@ -55,6 +55,18 @@ func validateDirectorType() schema.SchemaValidateDiagFunc {
 	return validation.ToDiagFunc(validation.IntInSlice([]int{1, 3, 4}))
 }
 
+func validatePoolQuorum() schema.SchemaValidateDiagFunc {
+	return validation.ToDiagFunc(validation.IntBetween(0, 100))
+}
+
+func validatePoolType() schema.SchemaValidateDiagFunc {
+	return validation.ToDiagFunc(validation.StringInSlice([]string{
+		string(gofastly.PoolTypeRandom),
+		string(gofastly.PoolTypeHash),
+		string(gofastly.PoolTypeClient),
+	}, false))
+}
+
 func validateConditionType() schema.SchemaValidateDiagFunc {
 	return validation.ToDiagFunc(validation.StringInSlice([]string{
 		"REQUEST",
@@ -157,6 +169,28 @@ func validatePEMBlock(pemType string) schema.SchemaValidateDiagFunc {
 	})
 }
 
+// validateTokenScope checks whether a string is a space-separated list of token scopes.
+func validateTokenScope() schema.SchemaValidateDiagFunc {
+	scopes := []string{
+		string(gofastly.GlobalScope),
+		string(gofastly.PurgeSelectScope),
+		string(gofastly.PurgeAllScope),
+		string(gofastly.GlobalReadScope),
+	}
+	return validation.ToDiagFunc(func(val interface{}, key string) ([]string, []error) {
+		fields := strings.Fields(val.(string))
+		if len(fields) == 0 {
+			return nil, []error{fmt.Errorf("expected %s to be one or more of %v, got %q", key, scopes, val)}
+		}
+		var errs []error
+		for _, f := range fields {
+			_, es := validation.StringInSlice(scopes, false)(f, key)
+			errs = append(errs, es...)
+		}
+		return nil, errs
+	})
+}
+
 // validatePEMBlocks returns a schema validation function that checks whether a string contains multiple PEM blocks of
 // type `pemType`.
 func validatePEMBlocks(pemType string) schema.SchemaValidateDiagFunc {
@@ -158,6 +158,54 @@ func TestValidateDirectorType(t *testing.T) {
 	}
 }
 
+func TestValidatePoolQuorum(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		value          int
+		expectedWarns  int
+		expectedErrors int
+	}{
+		"-1":  {-1, 0, 1},
+		"0":   {0, 0, 0},
+		"75":  {75, 0, 0},
+		"100": {100, 0, 0},
+		"101": {101, 0, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			actualWarns, actualErrors := diagToWarnsAndErrs(validatePoolQuorum()(testcase.value, cty.GetAttrPath("quorum")))
+			if len(actualWarns) != testcase.expectedWarns {
+				t.Errorf("expected %d warnings, actual %d ", testcase.expectedWarns, len(actualWarns))
+			}
+			if len(actualErrors) != testcase.expectedErrors {
+				t.Errorf("expected %d errors, actual %d ", testcase.expectedErrors, len(actualErrors))
+			}
+		})
+	}
+}
+
+func TestValidatePoolType(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		value          string
+		expectedWarns  int
+		expectedErrors int
+	}{
+		"random":      {"random", 0, 0},
+		"hash":        {"hash", 0, 0},
+		"client":      {"client", 0, 0},
+		"round_robin": {"round_robin", 0, 1},
+		"RANDOM":      {"RANDOM", 0, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			actualWarns, actualErrors := diagToWarnsAndErrs(validatePoolType()(testcase.value, cty.GetAttrPath("type")))
+			if len(actualWarns) != testcase.expectedWarns {
+				t.Errorf("expected %d warnings, actual %d ", testcase.expectedWarns, len(actualWarns))
+			}
+			if len(actualErrors) != testcase.expectedErrors {
+				t.Errorf("expected %d errors, actual %d ", testcase.expectedErrors, len(actualErrors))
+			}
+		})
+	}
+}
+
 func TestValidateConditionType(t *testing.T) {
 	for _, testcase := range []struct {
 		value          string
@@ -368,6 +416,33 @@ func TestValidateUserRole(t *testing.T) {
 	}
 }
 
+func TestValidateTokenScope(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		value          string
+		expectedWarns  int
+		expectedErrors int
+	}{
+		"global":       {"global", 0, 0},
+		"purge_select": {"purge_select", 0, 0},
+		"purge_all":    {"purge_all", 0, 0},
+		"global:read":  {"global:read", 0, 0},
+		"read":         {"read", 0, 1},
+		"multiple":     {"global:read purge_select", 0, 0},
+		"invalid":      {"global:read read", 0, 1},
+		"empty":        {"", 0, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			actualWarns, actualErrors := diagToWarnsAndErrs(validateTokenScope()(testcase.value, cty.GetAttrPath("scope")))
+			if len(actualWarns) != testcase.expectedWarns {
+				t.Errorf("expected %d warnings, actual %d ", testcase.expectedWarns, len(actualWarns))
+			}
+			if len(actualErrors) != testcase.expectedErrors {
+				t.Errorf("expected %d errors, actual %d ", testcase.expectedErrors, len(actualErrors))
+			}
+		})
+	}
+}
+
 func TestValidatePEMCertificate(t *testing.T) {
 	key, cert, ca, err := generateKeyAndCertWithCA()
 	if err != nil {
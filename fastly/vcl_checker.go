@@ -0,0 +1,424 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// vclSubroutines are the subroutines of the Fastly VCL state machine, which
+// are also the valid arguments of #FASTLY macros.
+var vclSubroutines = []string{"recv", "hash", "hit", "miss", "pass", "fetch", "error", "deliver", "log"}
+
+// vclDeclarations are the keywords starting a declaration, which may only be
+// made outside of subroutines.
+var vclDeclarations = []string{"sub", "acl", "table", "backend", "director", "penaltybox", "ratecounter"}
+
+// vclStatements are the keywords starting a statement, which may only be made
+// inside of subroutines.
+var vclStatements = []string{"set", "unset", "add", "remove", "declare", "if", "return", "call", "error", "restart", "esi", "synthetic", "log"}
+
+const (
+	// vclPlacementTopLevel is the placement of custom VCL and of snippets of
+	// type init, outside of any subroutine.
+	vclPlacementTopLevel = ""
+	// vclPlacementUnknown is the placement of code which may be placed either
+	// outside or inside a subroutine, such as dynamic snippet content and
+	// snippets of type none, which are only included by the custom VCL.
+	vclPlacementUnknown = "?"
+)
+
+// vclIssue is a problem found in VCL code, on the given line.
+type vclIssue struct {
+	line    int
+	message string
+}
+
+// vclSnippetPlacement returns where the code of a snippet of the given type is
+// placed: outside of subroutines for init, anywhere the custom VCL includes it
+// for none, otherwise inside the subroutine of the same name.
+func vclSnippetPlacement(snippetType string) string {
+	switch t := strings.ToLower(snippetType); t {
+	case "init":
+		return vclPlacementTopLevel
+	case "none":
+		return vclPlacementUnknown
+	default:
+		return t
+	}
+}
+
+type vclTokenKind int
+
+const (
+	vclTokenIdent vclTokenKind = iota
+	vclTokenString
+	vclTokenOpenBrace
+	vclTokenCloseBrace
+	vclTokenSemicolon
+	vclTokenMacro
+	vclTokenOther
+)
+
+type vclToken struct {
+	kind  vclTokenKind
+	value string
+	line  int
+}
+
+// lexVCL splits VCL code into tokens, skipping whitespace and comments.
+// #FASTLY macros, which are written as comments, are kept as tokens.
+func lexVCL(content string) ([]vclToken, []vclIssue) {
+	var tokens []vclToken
+	var issues []vclIssue
+
+	src := []rune(content)
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+
+		case c == '#':
+			end := vclLineEnd(src, i)
+			text := string(src[i:end])
+			if strings.HasPrefix(text, "#FASTLY") {
+				tokens = append(tokens, vclToken{kind: vclTokenMacro, value: strings.TrimSpace(strings.TrimPrefix(text, "#FASTLY")), line: line})
+			}
+			i = end
+
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			i = vclLineEnd(src, i)
+
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			start := line
+			i += 2
+			for i < len(src) && !(src[i] == '*' && i+1 < len(src) && src[i+1] == '/') {
+				if src[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(src) {
+				issues = append(issues, vclIssue{start, "unterminated comment"})
+				break
+			}
+			i += 2
+
+		case c == '"':
+			end := i + 1
+			for end < len(src) && src[end] != '"' && src[end] != '\n' {
+				end++
+			}
+			if end >= len(src) || src[end] != '"' {
+				issues = append(issues, vclIssue{line, "unterminated string"})
+				i = end
+				break
+			}
+			tokens = append(tokens, vclToken{kind: vclTokenString, value: string(src[i+1 : end]), line: line})
+			i = end + 1
+
+		case c == '{':
+			// Long strings are written {"..."} or {delimiter"..."delimiter}.
+			delim := i + 1
+			for delim < len(src) && isVCLDelimiterRune(src[delim]) {
+				delim++
+			}
+			if delim < len(src) && src[delim] == '"' {
+				closing := []rune(`"` + string(src[i+1:delim]) + `}`)
+				start := line
+				end := delim + 1
+				for end < len(src) && !hasRunePrefix(src[end:], closing) {
+					if src[end] == '\n' {
+						line++
+					}
+					end++
+				}
+				if end >= len(src) {
+					issues = append(issues, vclIssue{start, "unterminated long string"})
+					i = end
+					break
+				}
+				tokens = append(tokens, vclToken{kind: vclTokenString, value: string(src[delim+1 : end]), line: start})
+				i = end + len(closing)
+				break
+			}
+			tokens = append(tokens, vclToken{kind: vclTokenOpenBrace, value: "{", line: line})
+			i++
+
+		case c == '}':
+			tokens = append(tokens, vclToken{kind: vclTokenCloseBrace, value: "}", line: line})
+			i++
+
+		case c == ';':
+			tokens = append(tokens, vclToken{kind: vclTokenSemicolon, value: ";", line: line})
+			i++
+
+		case isVCLIdentStart(c):
+			end := i + 1
+			for end < len(src) && isVCLIdentRune(src[end]) {
+				end++
+			}
+			tokens = append(tokens, vclToken{kind: vclTokenIdent, value: string(src[i:end]), line: line})
+			i = end
+
+		default:
+			tokens = append(tokens, vclToken{kind: vclTokenOther, value: string(c), line: line})
+			i++
+		}
+	}
+
+	return tokens, issues
+}
+
+// vclFrame is a block of code which has been opened by a brace.
+type vclFrame struct {
+	// sub is the name of the subroutine the block belongs to.
+	sub  string
+	line int
+	// declaration is set for the body of a declaration other than a
+	// subroutine, such as a table, whose content is not checked.
+	declaration bool
+}
+
+// checkVCL checks the syntax of VCL code placed as given: outside of
+// subroutines, inside the subroutine vcl_<placement>, or either when the
+// placement is unknown. It reports unbalanced braces, subroutines which are
+// not part of the Fastly VCL state machine, invalid #FASTLY macros, and code
+// which is not valid at its placement.
+func checkVCL(content, placement string) []vclIssue {
+	tokens, issues := lexVCL(content)
+
+	// The frames of the blocks currently open.
+	var frames []vclFrame
+	if placement != vclPlacementTopLevel && placement != vclPlacementUnknown {
+		frames = append(frames, vclFrame{sub: "vcl_" + placement})
+	}
+	base := len(frames)
+
+	currentSub := func() string {
+		if len(frames) == 0 {
+			return ""
+		}
+		return frames[len(frames)-1].sub
+	}
+	inDeclaration := func() bool {
+		return len(frames) > 0 && frames[len(frames)-1].declaration
+	}
+
+	statementStart := true
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		atStart := statementStart
+		statementStart = false
+
+		switch t.kind {
+		case vclTokenOpenBrace:
+			f := vclFrame{sub: currentSub(), line: t.line, declaration: inDeclaration()}
+			if f.sub == "" && placement != vclPlacementUnknown {
+				// A brace outside of a subroutine opens the body of a
+				// declaration, such as a table or a backend.
+				f.declaration = true
+			}
+			frames = append(frames, f)
+			statementStart = true
+
+		case vclTokenCloseBrace:
+			if len(frames) == base {
+				issues = append(issues, vclIssue{t.line, `unexpected "}", there is no matching "{"`})
+			} else {
+				frames = frames[:len(frames)-1]
+			}
+			statementStart = true
+
+		case vclTokenSemicolon:
+			statementStart = true
+
+		case vclTokenMacro:
+			issues = append(issues, checkVCLMacro(t, currentSub(), placement)...)
+			statementStart = true
+
+		case vclTokenIdent:
+			if !atStart || inDeclaration() {
+				break
+			}
+			sub := currentSub()
+			switch {
+			case t.value == "sub":
+				if sub != "" {
+					issues = append(issues, vclIssue{t.line, fmt.Sprintf("subroutines cannot be declared inside %s%s", sub, vclPlacementHint(placement))})
+				}
+				if i+1 < len(tokens) && tokens[i+1].kind == vclTokenIdent {
+					name := tokens[i+1].value
+					if strings.HasPrefix(name, "vcl_") && !containsString(vclSubroutines, strings.TrimPrefix(name, "vcl_")) {
+						issues = append(issues, vclIssue{tokens[i+1].line, fmt.Sprintf("unknown subroutine %s, Fastly VCL subroutines are vcl_%s", name, strings.Join(vclSubroutines, ", vcl_"))})
+					}
+					if i+2 < len(tokens) && tokens[i+2].kind == vclTokenOpenBrace {
+						frames = append(frames, vclFrame{sub: name, line: tokens[i+2].line})
+						statementStart = true
+						i += 2
+					}
+				}
+			case sub != "" && containsString(vclDeclarations, t.value):
+				issues = append(issues, vclIssue{t.line, fmt.Sprintf("%s cannot be declared inside %s%s", t.value, sub, vclPlacementHint(placement))})
+			case sub == "" && placement != vclPlacementUnknown && containsString(vclStatements, t.value):
+				issues = append(issues, vclIssue{t.line, fmt.Sprintf("%s statement outside of a subroutine%s", t.value, vclPlacementHint(placement))})
+			}
+		}
+	}
+
+	for _, f := range frames[base:] {
+		issues = append(issues, vclIssue{f.line, `"{" is never closed`})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].line < issues[j].line
+	})
+	return issues
+}
+
+// checkVCLMacro checks a #FASTLY macro is for a subroutine of the Fastly VCL
+// state machine, and is placed inside that subroutine.
+func checkVCLMacro(t vclToken, sub, placement string) []vclIssue {
+	var name string
+	if fields := strings.Fields(t.value); len(fields) > 0 {
+		name = strings.ToLower(fields[0])
+	}
+	if !containsString(vclSubroutines, name) {
+		return []vclIssue{{t.line, fmt.Sprintf("invalid macro #FASTLY %s, the macro must be followed by one of %s", t.value, strings.Join(vclSubroutines, ", "))}}
+	}
+	if placement == vclPlacementUnknown {
+		return nil
+	}
+	if sub != "vcl_"+name {
+		where := "outside of a subroutine"
+		if sub != "" {
+			where = "in " + sub
+		}
+		return []vclIssue{{t.line, fmt.Sprintf("macro #FASTLY %s must be placed in vcl_%s, not %s", name, name, where)}}
+	}
+	return nil
+}
+
+// vclPlacementHint explains how the placement of a snippet is chosen when
+// code is not valid at its placement.
+func vclPlacementHint(placement string) string {
+	switch placement {
+	case vclPlacementTopLevel, vclPlacementUnknown:
+		return ""
+	default:
+		return fmt.Sprintf(", snippets of type %s are placed in vcl_%s, use type init for declarations", placement, placement)
+	}
+}
+
+// validateVCLSyntax returns a CustomizeDiffFunc which checks the syntax of the
+// content of every changed element of the given block when the provider has
+// vcl_syntax_check enabled. placement returns where the code of an element is
+// placed.
+func validateVCLSyntax(key string, placement func(map[string]interface{}) string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		client, ok := meta.(*FastlyClient)
+		if !ok || client == nil || !client.vclSyntaxCheck {
+			return nil
+		}
+		if !d.HasChange(key) || !d.NewValueKnown(key) {
+			return nil
+		}
+
+		var errs []string
+		for _, e := range blockElements(d.Get(key)) {
+			content, _ := e["content"].(string)
+			name, _ := e["name"].(string)
+			if content == "" || content == unknownValue {
+				continue
+			}
+			path := cty.GetAttrPath(key).IndexString(name).GetAttr("content")
+			errs = append(errs, formatVCLIssues(path, checkVCL(content, placement(e)))...)
+		}
+
+		if len(errs) > 0 {
+			sort.Strings(errs)
+			return fmt.Errorf("%s", strings.Join(errs, "\n"))
+		}
+		return nil
+	}
+}
+
+// validateDynamicSnippetContentSyntax is the CustomizeDiffFunc of dynamic
+// snippet content. The type of the snippet is defined by the service, so the
+// placement of the code is not checked.
+func validateDynamicSnippetContentSyntax(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	client, ok := meta.(*FastlyClient)
+	if !ok || client == nil || !client.vclSyntaxCheck {
+		return nil
+	}
+	content, _ := d.Get("content").(string)
+	if !d.HasChange("content") || !d.NewValueKnown("content") || content == "" {
+		return nil
+	}
+
+	errs := formatVCLIssues(cty.GetAttrPath("content"), checkVCL(content, vclPlacementUnknown))
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func formatVCLIssues(path cty.Path, issues []vclIssue) []string {
+	var result []string
+	for _, issue := range issues {
+		result = append(result, fmt.Sprintf("%s:%d: %s", formatAttributePath(path), issue.line, issue.message))
+	}
+	return result
+}
+
+func vclLineEnd(src []rune, i int) int {
+	for i < len(src) && src[i] != '\n' {
+		i++
+	}
+	return i
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isVCLIdentStart(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isVCLIdentRune reports whether c can be part of an identifier. Identifiers
+// include the dots, dashes and colons of variables such as req.http.X-Foo.
+func isVCLIdentRune(c rune) bool {
+	return isVCLIdentStart(c) || (c >= '0' && c <= '9') || c == '.' || c == '-' || c == ':'
+}
+
+func isVCLDelimiterRune(c rune) bool {
+	return isVCLIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
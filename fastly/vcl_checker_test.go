@@ -0,0 +1,177 @@
+package fastly
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testVCLMain = `
+# A comment with an unbalanced { brace
+sub vcl_recv {
+#FASTLY recv
+  if (req.http.X-Foo ~ "}") {
+    set req.http.X-Bar = {"a long
+string with a } brace"};
+  } else {
+    /* a comment
+       with a { brace */
+    call custom;
+  }
+  return(lookup);
+}
+
+table redirects {
+  "/old": "/new",
+}
+
+backend F_origin {
+  .host = "example.com";
+  .probe = {
+    .request = "GET / HTTP/1.1";
+  }
+}
+
+sub custom {
+  set req.http.X-Baz = {xyz"a "} string"xyz};
+}
+
+sub vcl_deliver {
+#FASTLY deliver
+  return(deliver);
+}
+`
+
+func TestCheckVCL(t *testing.T) {
+	cases := map[string]struct {
+		content   string
+		placement string
+		expected  []vclIssue
+	}{
+		"valid main VCL": {
+			content: testVCLMain,
+		},
+		"valid recv snippet": {
+			content:   "if (req.url ~ \"^/admin\") {\n  error 403;\n}\n",
+			placement: "recv",
+		},
+		"unclosed brace": {
+			content: "sub vcl_recv {\n  if (req.url) {\n    set req.http.X = \"1\";\n}\n",
+			expected: []vclIssue{
+				{1, `"{" is never closed`},
+			},
+		},
+		"unexpected brace": {
+			content: "sub vcl_recv {\n}\n}\n",
+			expected: []vclIssue{
+				{3, `unexpected "}", there is no matching "{"`},
+			},
+		},
+		"unexpected brace in snippet": {
+			content:   "set req.http.X = \"1\";\n}\n",
+			placement: "recv",
+			expected: []vclIssue{
+				{2, `unexpected "}", there is no matching "{"`},
+			},
+		},
+		"unterminated string": {
+			content: "sub vcl_recv {\n  set req.http.X = \"1;\n}\n",
+			expected: []vclIssue{
+				{2, "unterminated string"},
+			},
+		},
+		"unknown subroutine": {
+			content: "sub vcl_receive {\n}\n",
+			expected: []vclIssue{
+				{1, "unknown subroutine vcl_receive, Fastly VCL subroutines are vcl_recv, vcl_hash, vcl_hit, vcl_miss, vcl_pass, vcl_fetch, vcl_error, vcl_deliver, vcl_log"},
+			},
+		},
+		"invalid macro": {
+			content: "sub vcl_recv {\n#FASTLY receive\n}\n",
+			expected: []vclIssue{
+				{2, "invalid macro #FASTLY receive, the macro must be followed by one of recv, hash, hit, miss, pass, fetch, error, deliver, log"},
+			},
+		},
+		"misplaced macro": {
+			content: "sub vcl_recv {\n#FASTLY deliver\n}\n#FASTLY recv\n",
+			expected: []vclIssue{
+				{2, "macro #FASTLY deliver must be placed in vcl_deliver, not in vcl_recv"},
+				{4, "macro #FASTLY recv must be placed in vcl_recv, not outside of a subroutine"},
+			},
+		},
+		"subroutine in a recv snippet": {
+			content:   "sub custom {\n}\n",
+			placement: "recv",
+			expected: []vclIssue{
+				{1, "subroutines cannot be declared inside vcl_recv, snippets of type recv are placed in vcl_recv, use type init for declarations"},
+			},
+		},
+		"table in a deliver snippet": {
+			content:   "table t {\n}\n",
+			placement: "deliver",
+			expected: []vclIssue{
+				{1, "table cannot be declared inside vcl_deliver, snippets of type deliver are placed in vcl_deliver, use type init for declarations"},
+			},
+		},
+		"statement in an init snippet": {
+			content: "set req.http.X = \"1\";\n",
+			expected: []vclIssue{
+				{1, "set statement outside of a subroutine"},
+			},
+		},
+		"dynamic snippet content": {
+			content:   "set req.http.X = \"1\";\n#FASTLY recv\n",
+			placement: vclPlacementUnknown,
+		},
+		"statement in a none snippet": {
+			content:   "set req.http.X = \"1\";\n",
+			placement: vclSnippetPlacement("none"),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			issues := checkVCL(c.content, c.placement)
+			if !reflect.DeepEqual(issues, c.expected) {
+				t.Errorf("Error matching issues:\nexpected: %#v\n     got: %#v", c.expected, issues)
+			}
+		})
+	}
+}
+
+func TestValidateVCLSyntax(t *testing.T) {
+	raw := map[string]interface{}{
+		"name": "test",
+		"domain": []interface{}{
+			map[string]interface{}{"name": "example.com"},
+		},
+		"vcl": []interface{}{
+			map[string]interface{}{"name": "main", "content": "sub vcl_recv {\n", "main": true},
+		},
+		"snippet": []interface{}{
+			map[string]interface{}{"name": "recv", "type": "recv", "content": "sub custom {\n}\n"},
+		},
+	}
+
+	// The syntax is only checked when enabled in the provider.
+	_, err := resourceServiceV1().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), &FastlyClient{})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	_, err = resourceServiceV1().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), &FastlyClient{noAuth: true, vclSyntaxCheck: true})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, msg := range []string{
+		`vcl["main"].content:1: "{" is never closed`,
+		`snippet["recv"].content:1: subroutines cannot be declared inside vcl_recv`,
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("expected %q in %q", msg, err)
+		}
+	}
+}
@@ -78,10 +78,42 @@ func main() {
 	defer os.RemoveAll(tempDir)
 
 	var dataPages = []Page{
+		{
+			name: "datacenters",
+			path: tempDir + "/data-sources/datacenters.md.tmpl",
+		},
+		{
+			name: "edge_check",
+			path: tempDir + "/data-sources/edge_check.md.tmpl",
+		},
+		{
+			name: "events",
+			path: tempDir + "/data-sources/events.md.tmpl",
+		},
 		{
 			name: "ip_ranges",
 			path: tempDir + "/data-sources/ip_ranges.md.tmpl",
 		},
+		{
+			name: "service",
+			path: tempDir + "/data-sources/service.md.tmpl",
+		},
+		{
+			name: "service_generated_vcl",
+			path: tempDir + "/data-sources/service_generated_vcl.md.tmpl",
+		},
+		{
+			name: "service_stats",
+			path: tempDir + "/data-sources/service_stats.md.tmpl",
+		},
+		{
+			name: "service_version_diff",
+			path: tempDir + "/data-sources/service_version_diff.md.tmpl",
+		},
+		{
+			name: "services",
+			path: tempDir + "/data-sources/services.md.tmpl",
+		},
 		{
 			name: "data_source_tls_activation",
 			path: tempDir + "/data-sources/tls_activation.md.tmpl",
@@ -134,6 +166,14 @@ func main() {
 			name: "data_source_tls_subscription_ids",
 			path: tempDir + "/data-sources/tls_subscription_ids.md.tmpl",
 		},
+		{
+			name: "tokens",
+			path: tempDir + "/data-sources/tokens.md.tmpl",
+		},
+		{
+			name: "usage",
+			path: tempDir + "/data-sources/usage.md.tmpl",
+		},
 		{
 			name: "waf_rules",
 			path: tempDir + "/data-sources/waf_rules.md.tmpl",
@@ -167,10 +207,22 @@ func main() {
 			name: "service_dynamic_snippet_content_v1",
 			path: tempDir + "/resources/service_dynamic_snippet_content_v1.md.tmpl",
 		},
+		{
+			name: "service_activation",
+			path: tempDir + "/resources/service_activation.md.tmpl",
+		},
+		{
+			name: "service_purge",
+			path: tempDir + "/resources/service_purge.md.tmpl",
+		},
 		{
 			name: "service_waf_configuration",
 			path: tempDir + "/resources/service_waf_configuration.md.tmpl",
 		},
+		{
+			name: "token",
+			path: tempDir + "/resources/token.md.tmpl",
+		},
 		{
 			name: "user_v1",
 			path: tempDir + "/resources/user_v1.md.tmpl",
@@ -183,6 +235,10 @@ func main() {
 			name: "tls_certificate",
 			path: tempDir + "/resources/tls_certificate.md.tmpl",
 		},
+		{
+			name: "tls_configuration",
+			path: tempDir + "/resources/tls_configuration.md.tmpl",
+		},
 		{
 			name: "tls_platform_certificate",
 			path: tempDir + "/resources/tls_platform_certificate.md.tmpl",